@@ -0,0 +1,91 @@
+package bind
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrConstraintViolation is the failure of the checks, ezapi reports it
+// as its own ErrConstraintViolation
+var ErrConstraintViolation = errors.New("constraint violation")
+
+const (
+	// supported formats
+	_FORMAT_EMAIL = "email"
+	_FORMAT_UUID  = "uuid"
+	_FORMAT_URI   = "uri"
+)
+
+// The checks of the single constraints, shared with the reflection based binding
+
+func CheckMin(n, min float64) error {
+	if n < min {
+		return fmt.Errorf("%w: must be at least %v", ErrConstraintViolation, min)
+	}
+	return nil
+}
+
+func CheckMax(n, max float64) error {
+	if n > max {
+		return fmt.Errorf("%w: must be at most %v", ErrConstraintViolation, max)
+	}
+	return nil
+}
+
+func CheckMinLen(n, min int) error {
+	if n < min {
+		return fmt.Errorf("%w: length must be at least %d", ErrConstraintViolation, min)
+	}
+	return nil
+}
+
+func CheckMaxLen(n, max int) error {
+	if n > max {
+		return fmt.Errorf("%w: length must be at most %d", ErrConstraintViolation, max)
+	}
+	return nil
+}
+
+func CheckPattern(re *regexp.Regexp, s string) error {
+	if !re.MatchString(s) {
+		return fmt.Errorf("%w: must match the pattern %s", ErrConstraintViolation, re)
+	}
+	return nil
+}
+
+func CheckEnum(s string, values ...string) error {
+	for _, value := range values {
+		if value == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: must be one of %s", ErrConstraintViolation, strings.Join(values, "|"))
+}
+
+// CheckFormat checks the string against the format, empty strings are checked by minLen only
+func CheckFormat(format, s string) error {
+	if s == "" {
+		return nil
+	}
+	switch format {
+	case _FORMAT_EMAIL:
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return fmt.Errorf("%w: must be a valid email", ErrConstraintViolation)
+		}
+	case _FORMAT_UUID:
+		if _, err := uuid.Parse(s); err != nil {
+			return fmt.Errorf("%w: must be a valid uuid", ErrConstraintViolation)
+		}
+	case _FORMAT_URI:
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" {
+			return fmt.Errorf("%w: must be a valid uri", ErrConstraintViolation)
+		}
+	}
+	return nil
+}
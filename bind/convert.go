@@ -0,0 +1,154 @@
+package bind
+
+import (
+	"encoding"
+	"strconv"
+	"time"
+)
+
+// The conversions of the raw values. The destination is passed as a pointer,
+// so the field types are inferred and never spelled out
+
+// Alloc sets the pointer to a new value and returns it
+func Alloc[T any](p **T) *T {
+	*p = new(T)
+	return *p
+}
+
+// Value sets the context value if it has the type of the field
+func Value[T any](dst *T, value any) bool {
+	v, ok := value.(T)
+	if ok {
+		*dst = v
+	}
+	return ok
+}
+
+func String[T ~string](dst *T, s string) error {
+	*dst = T(s)
+	return nil
+}
+
+func Bytes[T ~[]byte](dst *T, s string) error {
+	*dst = T(s)
+	return nil
+}
+
+func Int[T ~int](dst *T, s string) error {
+	return parseInt(dst, s, strconv.IntSize)
+}
+
+func Int8[T ~int8](dst *T, s string) error {
+	return parseInt(dst, s, 8)
+}
+
+func Int16[T ~int16](dst *T, s string) error {
+	return parseInt(dst, s, 16)
+}
+
+func Int32[T ~int32](dst *T, s string) error {
+	return parseInt(dst, s, 32)
+}
+
+func Int64[T ~int64](dst *T, s string) error {
+	return parseInt(dst, s, 64)
+}
+
+func parseInt[T ~int | ~int8 | ~int16 | ~int32 | ~int64](dst *T, s string, bitSize int) error {
+	n, err := strconv.ParseInt(s, 10, bitSize)
+	if err != nil {
+		return err
+	}
+	*dst = T(n)
+	return nil
+}
+
+func Uint[T ~uint](dst *T, s string) error {
+	return parseUint(dst, s, strconv.IntSize)
+}
+
+func Uint8[T ~uint8](dst *T, s string) error {
+	return parseUint(dst, s, 8)
+}
+
+func Uint16[T ~uint16](dst *T, s string) error {
+	return parseUint(dst, s, 16)
+}
+
+func Uint32[T ~uint32](dst *T, s string) error {
+	return parseUint(dst, s, 32)
+}
+
+func Uint64[T ~uint64](dst *T, s string) error {
+	return parseUint(dst, s, 64)
+}
+
+func parseUint[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64](dst *T, s string, bitSize int) error {
+	n, err := strconv.ParseUint(s, 10, bitSize)
+	if err != nil {
+		return err
+	}
+	*dst = T(n)
+	return nil
+}
+
+func Float32[T ~float32](dst *T, s string) error {
+	n, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		return err
+	}
+	*dst = T(n)
+	return nil
+}
+
+func Float64[T ~float64](dst *T, s string) error {
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*dst = T(n)
+	return nil
+}
+
+func Bool[T ~bool](dst *T, s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*dst = T(b)
+	return nil
+}
+
+func Duration(dst *time.Duration, s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*dst = d
+	return nil
+}
+
+// Text binds the types implementing encoding.TextUnmarshaler, e.g. uuid.UUID
+func Text[T any, PT interface {
+	*T
+	encoding.TextUnmarshaler
+}](dst PT, s string) error {
+	var v T
+	if err := PT(&v).UnmarshalText([]byte(s)); err != nil {
+		return err
+	}
+	*dst = v
+	return nil
+}
+
+// Slice binds every item with the conversion of the item type
+func Slice[S ~[]E, E any](dst *S, items []string, bind func(*E, string) error) error {
+	slice := make(S, len(items))
+	for i, item := range items {
+		if err := bind(&slice[i], item); err != nil {
+			return err
+		}
+	}
+	*dst = slice
+	return nil
+}
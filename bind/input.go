@@ -0,0 +1,82 @@
+// Package bind is the runtime of the binders generated by `ezapi gen`.
+// The generated code calls it, it isn't meant to be used directly
+package bind
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Input is the raw data of the request, passed to the binders
+type Input struct {
+	Body io.Reader
+	// Codec selected by the Content-Type, nil if none matches
+	BodyCodec     Decoder
+	PathParams    map[string]string
+	QueryParams   map[string][]string
+	ContextValues map[string]any
+	Header        http.Header
+	Cookies       []*http.Cookie
+	Form          url.Values
+	MultipartForm *multipart.Form
+}
+
+// Decoder decodes the body, every ezapi.Codec is one
+type Decoder interface {
+	Decode(r io.Reader, v any) error
+}
+
+// ItemError is the failure of the item of a slice param
+func ItemError(i int, err error) error {
+	return fmt.Errorf("item %d: %w", i, err)
+}
+
+// FindCookie returns the cookie with the name, nil if there is none
+func FindCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// SplitHeaderValues splits the multi-value headers sent as several lines
+// or as a comma separated list
+func SplitHeaderValues(values []string) []string {
+	var parts []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	return parts
+}
+
+// NoValue reports whether the values of the optional or defaulted param are
+// missing: none at all, or a single empty one as in "?limit="
+func NoValue(values []string) bool {
+	return len(values) == 0 || (len(values) == 1 && values[0] == "")
+}
+
+// FormValues returns the values of the multipart form field
+func FormValues(form *multipart.Form, name string) []string {
+	if form == nil {
+		return nil
+	}
+	return form.Value[name]
+}
+
+// FormFiles returns the files uploaded with the multipart form field
+func FormFiles(form *multipart.Form, name string) []*multipart.FileHeader {
+	if form == nil {
+		return nil
+	}
+	return form.File[name]
+}
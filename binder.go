@@ -0,0 +1,124 @@
+package ezapi
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/ic-it/ezapi/bind"
+)
+
+// Binder binds and validates the request struct without reflection.
+// The binders are generated by `ezapi gen` and picked up by H
+type Binder[T any] func(in bind.Input) (T, error)
+
+var (
+	bindersMu sync.RWMutex
+	binders   = map[reflect.Type]any{}
+)
+
+// RegisterBinder registers the binder of the request struct, it is used for
+// the handlers taking the struct or a pointer to it
+func RegisterBinder[T any](binder Binder[T]) {
+	bindersMu.Lock()
+	defer bindersMu.Unlock()
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	binders[t] = binder
+	binders[reflect.PointerTo(t)] = Binder[*T](func(in bind.Input) (*T, error) {
+		req, err := binder(in)
+		return &req, err
+	})
+}
+
+// RegisterGeneratedBinder registers the binder generated by `ezapi gen` for
+// the request struct with the fingerprint. It panics if the fields or the tags
+// of the struct changed since, the binder would bind it with the stale ones
+func RegisterGeneratedBinder[T any](binder Binder[T], fingerprint string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if actual := binderFingerprint(t); actual != fingerprint {
+		panic(fmt.Sprintf("ezapi: the binder of %s was generated for other fields or tags (fingerprint %s, expected %s), run ezapi gen again",
+			t, fingerprint, actual))
+	}
+	RegisterBinder(binder)
+}
+
+// hash of the names and the tags of the section fields of the request struct
+// and of the fields of the section structs, the promoted fields aren't covered.
+// ezapi gen hashes the source of the struct the same way
+func binderFingerprint(t reflect.Type) string {
+	h := fnv.New64a()
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isSectionTag(field.Tag.Get(_EZAPI_TAG_NAME)) {
+			continue
+		}
+		fmt.Fprintf(h, "%s %q\n", field.Name, string(field.Tag))
+		st := field.Type
+		if st.Kind() == reflect.Ptr {
+			st = st.Elem()
+		}
+		if st.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < st.NumField(); j++ {
+			fmt.Fprintf(h, "\t%s %q\n", st.Field(j).Name, string(st.Field(j).Tag))
+		}
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+func isSectionTag(tag string) bool {
+	switch Section(tag) {
+	case SectionJSONBody, SectionBody, SectionPath, SectionQuery, SectionContext,
+		SectionHeader, SectionCookie, SectionForm, SectionMultipart:
+		return true
+	}
+	return false
+}
+
+// the registered binder of the request, if any
+func lookupBinder[T any]() (Binder[T], bool) {
+	bindersMu.RLock()
+	defer bindersMu.RUnlock()
+	binder, ok := binders[reflect.TypeOf((*T)(nil)).Elem()]
+	if !ok {
+		return nil, false
+	}
+	return binder.(Binder[T]), true
+}
+
+// adapt the binder to the signature of the reflection based unmarshaler
+func (binder Binder[T]) unmarshaler() unmarshaler[T] {
+	return func(
+		body io.Reader,
+		pathParams map[string]string,
+		queryParams map[string][]string,
+		contextValues map[string]any,
+		headers http.Header,
+		cookies []*http.Cookie,
+		form url.Values,
+		multipartForm *multipart.Form,
+		bodyCodec Codec,
+	) (T, error) {
+		return binder(bind.Input{
+			Body:          body,
+			BodyCodec:     bodyCodec,
+			PathParams:    pathParams,
+			QueryParams:   queryParams,
+			ContextValues: contextValues,
+			Header:        headers,
+			Cookies:       cookies,
+			Form:          form,
+			MultipartForm: multipartForm,
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package ezapi_test
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ic-it/ezapi"
+	"github.com/ic-it/ezapi/bind"
+	"github.com/ic-it/ezapi/internal/bindtest"
+)
+
+// the raw data of the request, every bind reads the body from the start
+type bindInput struct {
+	body          string
+	pathParams    map[string]string
+	queryParams   map[string][]string
+	contextValues map[string]any
+	headers       http.Header
+	cookies       []*http.Cookie
+	form          url.Values
+	multipartForm *multipart.Form
+}
+
+func (in bindInput) bindInput() bind.Input {
+	return bind.Input{
+		Body:          strings.NewReader(in.body),
+		BodyCodec:     ezapi.JSONCodec{},
+		PathParams:    in.pathParams,
+		QueryParams:   in.queryParams,
+		ContextValues: in.contextValues,
+		Header:        in.headers,
+		Cookies:       in.cookies,
+		Form:          in.form,
+		MultipartForm: in.multipartForm,
+	}
+}
+
+// the failed fields of the binding error, comparable between the binders
+func failedFields(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var verrs ezapi.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return []string{err.Error()}
+	}
+	fields := make([]string, len(verrs.Errors))
+	for i, fieldErr := range verrs.Errors {
+		fields[i] = fieldErr.Location + ": " + fieldErr.Reason
+	}
+	return fields
+}
+
+// bind the inputs with the reflection based unmarshaler and the generated binder
+func checkParity[T any](t *testing.T, compiled ezapi.Binder[T], inputs ...bindInput) {
+	t.Helper()
+	unmarshal := ezapi.BuildUnmarshaler[T](ezapi.ReflectReq[T]())
+	for i, in := range inputs {
+		raw := in.bindInput()
+		reflected, reflectErr := unmarshal(raw.Body, raw.PathParams, raw.QueryParams, raw.ContextValues,
+			raw.Header, raw.Cookies, raw.Form, raw.MultipartForm, ezapi.JSONCodec{})
+		generated, generatedErr := compiled(in.bindInput())
+
+		name := fmt.Sprintf("%T #%d", reflected, i)
+		// the partially bound requests of the failures may differ, e.g. in the
+		// pointers allocated before the failed conversion
+		if reflectErr == nil && !reflect.DeepEqual(reflected, generated) {
+			t.Errorf("%s: reflect bound %+v, generated %+v", name, reflected, generated)
+		}
+		if r, g := failedFields(reflectErr), failedFields(generatedErr); !reflect.DeepEqual(r, g) {
+			t.Errorf("%s: reflect failed with %q, generated with %q", name, r, g)
+		}
+	}
+}
+
+func TestBinderParity(t *testing.T) {
+	checkParity(t, bindtest.BindPath,
+		bindInput{pathParams: map[string]string{"id": "42", "slug": "first"}},
+		bindInput{pathParams: map[string]string{"id": "0", "slug": ""}},
+		bindInput{pathParams: map[string]string{"id": "x"}},
+	)
+	checkParity(t, bindtest.BindQuery,
+		bindInput{queryParams: map[string][]string{"limit": {"50"}, "tag": {"a", "b"}, "active": {"false"}}},
+		bindInput{queryParams: map[string][]string{"limit": {""}, "active": {""}}},
+		bindInput{queryParams: map[string][]string{"limit": {"500"}, "active": {"maybe"}}},
+		bindInput{},
+	)
+	checkParity(t, bindtest.BindContext,
+		bindInput{contextValues: map[string]any{"userId": "u-1", "count": 3}},
+		bindInput{contextValues: map[string]any{"userId": "u-1", "count": "3"}},
+		bindInput{contextValues: map[string]any{"count": 3.5}},
+	)
+	headers := http.Header{}
+	headers.Set("X-Request-Id", "7f1c2a3e-52d1-4c0b-9d2e-3b8f6a1c9e10")
+	headers.Add("Accept-Language", "en, de")
+	headers.Add("Accept-Language", "fr")
+	checkParity(t, bindtest.BindHeader,
+		bindInput{headers: headers},
+		bindInput{headers: http.Header{"X-Request-Id": {"not-a-uuid"}}},
+		bindInput{headers: http.Header{}},
+	)
+	checkParity(t, bindtest.BindCookie,
+		bindInput{cookies: []*http.Cookie{{Name: "session", Value: "s"}, {Name: "theme", Value: "dark"}}},
+		bindInput{cookies: []*http.Cookie{{Name: "theme", Value: "dark"}}},
+	)
+	checkParity(t, bindtest.BindForm,
+		bindInput{form: url.Values{"name": {"Ada"}, "age": {"36"}}},
+		bindInput{form: url.Values{"name": {""}, "age": {"-1"}}},
+	)
+	checkParity(t, bindtest.BindMultipart,
+		bindInput{},
+	)
+	checkParity(t, bindtest.BindJSONBody,
+		bindInput{body: `{"title":"buy milk","done":true}`},
+		bindInput{body: `{"title":""}`},
+		bindInput{body: `{`},
+	)
+	checkParity(t, bindtest.BindBody,
+		bindInput{body: `{"title":"buy milk"}`},
+		bindInput{body: `{"title":""}`},
+	)
+}
+
+type staleReq struct {
+	Query struct {
+		Limit int `ezapi:"limit"`
+	} `ezapi:"query"`
+}
+
+func TestRegisterStaleBinder(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "run ezapi gen again") {
+			t.Errorf("expected the stale binder to be rejected, got %v", r)
+		}
+	}()
+	ezapi.RegisterGeneratedBinder(func(bind.Input) (staleReq, error) {
+		return staleReq{}, nil
+	}, "stale")
+}
@@ -6,10 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/ic-it/ezapi/bind"
 )
 
 type unmarshaler[T any] func(
@@ -17,164 +23,371 @@ type unmarshaler[T any] func(
 	pathParams map[string]string,
 	queryParams map[string][]string,
 	contextValues map[string]any,
+	headers http.Header,
+	cookies []*http.Cookie,
+	form url.Values,
+	multipartForm *multipart.Form,
+	bodyCodec Codec,
 ) (T, error)
 
-// build unmarshaler for given reflectedReq
+// build unmarshaler for given reflectedReq.
+// The fields are looked up by their indexes and set by the setters compiled
+// in ReflectReq, the sections are bound in place into the request value
 func BuildUnmarshaler[T any](reflected reflectedReq) unmarshaler[T] {
+	reqType := reflected.typ
+	isPtr := reflected.isPtr
 
-	// json body unmarshaler
-	jsonBodyUnmarshaler := func(body io.Reader) (any, error) {
-		v := reflect.New(reflected.jsonBodyType).Interface()
-		dec := json.NewDecoder(body)
-		// dec.DisallowUnknownFields()
-		err := dec.Decode(v)
-		if err != nil {
-			return nil, err
+	// index of the section field in the request struct, -1 if there is no such section
+	sectionIndex := func(fieldName string, typ reflect.Type) int {
+		if typ == nil {
+			return -1
 		}
-		return v, nil
+		field, ok := reqType.FieldByName(fieldName)
+		if !ok {
+			return -1
+		}
+		return field.Index[0]
 	}
+	jsonBodyIndex := sectionIndex(reflected.jsonBodyFieldName, reflected.jsonBodyType)
+	bodyIndex := sectionIndex(reflected.bodyFieldName, reflected.bodyType)
+	pathParamsIndex := sectionIndex(reflected.pathParamsFieldName, reflected.pathParamsType)
+	queryParamsIndex := sectionIndex(reflected.queryParamsFieldName, reflected.queryParamsType)
+	contextValuesIndex := sectionIndex(reflected.contextValuesName, reflected.contextValuesType)
+	headersIndex := sectionIndex(reflected.headersFieldName, reflected.headersType)
+	cookiesIndex := sectionIndex(reflected.cookiesFieldName, reflected.cookiesType)
+	formIndex := sectionIndex(reflected.formFieldName, reflected.formType)
+	multipartIndex := sectionIndex(reflected.multipartFieldName, reflected.multipartType)
 
-	// path params deserializer
-	pathParamsUnmarshaler := func(pathParams map[string]string) (any, error) {
-		pathParamsType := reflected.pathParamsType
-		isPtr := pathParamsType.Kind() == reflect.Ptr
-		if pathParamsType.Kind() == reflect.Ptr {
-			pathParamsType = pathParamsType.Elem()
+	// only the body fields with constraints are checked
+	var bodyFields []reflectedKeyVal
+	for _, field := range reflected.bodyFields {
+		if field.constraints != nil {
+			bodyFields = append(bodyFields, field)
 		}
-		v := reflect.New(pathParamsType).Elem()
-		for _, param := range reflected.pathParams {
-			debugErr := fmt.Errorf("alias: %s, field: %s", param.alias, param.fieldName)
-			field := v.FieldByName(param.fieldName)
-			if !field.IsValid() {
-				return nil, errors.Join(ErrInvalidField, debugErr)
-			}
+	}
+	pathParamsFields := reflected.pathParams
+	queryParamsFields := reflected.queryParams
+	contextValuesFields := reflected.contextValues
+	headersFields := reflected.headers
+	cookiesFields := reflected.cookies
+	formFields := reflected.form
+	multipartFields := reflected.multipart
+
+	// body decoded into the field, by the json decoder or the codec selected by the Content-Type
+	bodyUnmarshaler := func(dst reflect.Value, body io.Reader, codec Codec) []FieldError {
+		if codec == nil {
+			return []FieldError{newFieldError(_EZAPI_TAG_BODY, "", nil, ErrUnsupportedMediaType, nil)}
+		}
+		if err := codec.Decode(body, dst.Addr().Interface()); err != nil {
+			return []FieldError{newFieldError(_EZAPI_TAG_BODY, "", nil, err, nil)}
+		}
+		return checkBodyConstraints(bodyFields, dst)
+	}
+
+	// path params deserializer
+	pathParamsUnmarshaler := func(dst reflect.Value, pathParams map[string]string) []FieldError {
+		v := sectionStruct(dst)
+		var fieldErrs []FieldError
+		for i := range pathParamsFields {
+			param := &pathParamsFields[i]
+			field := v.FieldByIndex(param.index)
 
 			value, ok := pathParams[param.alias]
-			if !ok {
+			if !ok || (value == "" && param.hasDefault()) {
+				if param.hasDefault() {
+					field.Set(param.defaultCopy())
+					continue
+				}
 				if !param.optional {
-					return nil, errors.Join(ErrMissingPathParam, debugErr)
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_PATH_PARAMS, param.alias, nil, ErrMissingPathParam, param.debugErr()))
 				}
 				continue
 			}
 			if value == "" && !param.optional {
-				return nil, errors.Join(ErrMissingPathParam, debugErr)
+				fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_PATH_PARAMS, param.alias, value, ErrMissingPathParam, param.debugErr()))
+				continue
 			}
 
-			unmarshaled, err := unmarshalStrToType(param.typ, value)
-			if err != nil {
-				return nil, errors.Join(err, debugErr)
+			if err := param.set(field, []string{value}); err != nil {
+				fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_PATH_PARAMS, param.alias, value, err, param.debugErr()))
+				continue
+			}
+			if param.constraints != nil {
+				if err := param.constraints.check(field); err != nil {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_PATH_PARAMS, param.alias, field.Interface(), err, param.debugErr()))
+				}
 			}
-
-			field.Set(reflect.ValueOf(unmarshaled))
-		}
-		if isPtr {
-			return v.Addr().Interface(), nil
 		}
-		return v.Interface(), nil
+		return fieldErrs
 	}
 
 	// query params deserializer
-	queryParamsUnmarshaler := func(queryParams map[string][]string) (any, error) {
-		queryParamsType := reflected.queryParamsType
-		isPtr := queryParamsType.Kind() == reflect.Ptr
-		if queryParamsType.Kind() == reflect.Ptr {
-			queryParamsType = queryParamsType.Elem()
-		}
-		v := reflect.New(queryParamsType).Elem()
-		for _, param := range reflected.queryParams {
-			debugErr := fmt.Errorf("alias: %s, field: %s", param.alias, param.fieldName)
-			field := v.FieldByName(param.fieldName)
-			if !field.IsValid() {
-				return nil, errors.Join(ErrInvalidField, debugErr)
-			}
-
-			values, ok := queryParams[param.alias]
-			if !ok {
+	queryParamsUnmarshaler := func(dst reflect.Value, queryParams map[string][]string) []FieldError {
+		v := sectionStruct(dst)
+		var fieldErrs []FieldError
+		for i := range queryParamsFields {
+			param := &queryParamsFields[i]
+			field := v.FieldByIndex(param.index)
+
+			values := queryParams[param.alias]
+			if len(values) == 0 || (param.omittable() && bind.NoValue(values)) {
+				if param.hasDefault() {
+					field.Set(param.defaultCopy())
+					continue
+				}
 				if !param.optional {
-					return nil, errors.Join(ErrMissingQueryParam, debugErr)
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_QUERY_PARAMS, param.alias, nil, ErrMissingQueryParam, param.debugErr()))
 				}
 				continue
 			}
 
-			var unmarshaled any
-			var err error
-			if param.typ.Kind() == reflect.Slice {
-				if len(values) == 0 {
-					unmarshaled = reflect.Zero(param.typ).Interface()
-				} else {
-					unmarshaled, err = unmarshalSliceToType(param.typ, values)
-					if err != nil {
-						return nil, errors.Join(err, debugErr)
-					}
+			if err := param.set(field, values); err != nil {
+				fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_QUERY_PARAMS, param.alias, values, err, param.debugErr()))
+				continue
+			}
+			if param.constraints != nil {
+				if err := param.constraints.check(field); err != nil {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_QUERY_PARAMS, param.alias, field.Interface(), err, param.debugErr()))
 				}
-			} else if param.typ.Kind() == reflect.String {
-				if len(values) == 0 {
-					unmarshaled = ""
-				} else {
-					unmarshaled = values[0]
+			}
+		}
+		return fieldErrs
+	}
+
+	// context values deserializer
+	contextValuesUnmarshaler := func(dst reflect.Value, contextValues map[string]any) []FieldError {
+		v := sectionStruct(dst)
+		var fieldErrs []FieldError
+		for i := range contextValuesFields {
+			param := &contextValuesFields[i]
+			field := v.FieldByIndex(param.index)
+
+			// the context returns nil for the missing keys
+			value := contextValues[param.alias]
+			if value == nil {
+				if param.hasDefault() {
+					field.Set(param.defaultCopy())
+					continue
+				}
+				if !param.optional {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_CONTEXT, param.alias, nil, ErrMissingContextValue, param.debugErr()))
+				}
+				continue
+			}
+
+			if str, ok := value.(string); ok && param.typ != reflect.TypeOf(str) {
+				// string values are converted to the field type
+				if param.set == nil {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_CONTEXT, param.alias, nil, ErrTypeMismatch,
+						errors.Join(param.debugErr(), fmt.Errorf("expected type %v, got string", param.typ)),
+					))
+					continue
+				}
+				if err := param.set(field, []string{str}); err != nil {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_CONTEXT, param.alias, str, err, param.debugErr()))
+					continue
 				}
 			} else {
-				return nil, errors.Join(ErrorUnsuppType, debugErr)
+				// check type mismatch
+				if reflect.TypeOf(value) != param.typ {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_CONTEXT, param.alias, nil, ErrTypeMismatch,
+						errors.Join(param.debugErr(), fmt.Errorf("expected type %v, got %v", param.typ, reflect.TypeOf(value))),
+					))
+					continue
+				}
+				field.Set(reflect.ValueOf(value))
 			}
 
-			field.Set(reflect.ValueOf(unmarshaled))
-		}
-		if isPtr {
-			return v.Addr().Interface(), nil
+			if param.constraints != nil {
+				if err := param.constraints.check(field); err != nil {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_CONTEXT, param.alias, field.Interface(), err, param.debugErr()))
+				}
+			}
 		}
-		return v.Interface(), nil
+		return fieldErrs
 	}
 
-	contextValuesUnmarshaler := func(contextValues map[string]any) (any, error) {
-		contextValuesType := reflected.contextValuesType
-		isPtr := contextValuesType.Kind() == reflect.Ptr
-		if contextValuesType.Kind() == reflect.Ptr {
-			contextValuesType = contextValuesType.Elem()
-		}
-		v := reflect.New(contextValuesType).Elem()
-		for _, param := range reflected.contextValues {
-			debugErr := fmt.Errorf("alias: %s, field: %s", param.alias, param.fieldName)
-			field := v.FieldByName(param.fieldName)
-			if !field.IsValid() {
-				return nil, errors.Join(ErrInvalidField, debugErr)
+	// headers deserializer
+	headersUnmarshaler := func(dst reflect.Value, headers http.Header) []FieldError {
+		v := sectionStruct(dst)
+		var fieldErrs []FieldError
+		for i := range headersFields {
+			param := &headersFields[i]
+			field := v.FieldByIndex(param.index)
+
+			values := headers.Values(param.alias)
+			if len(values) == 0 || (param.omittable() && bind.NoValue(values)) {
+				if param.hasDefault() {
+					field.Set(param.defaultCopy())
+					continue
+				}
+				if !param.optional {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_HEADERS, param.alias, nil, ErrMissingHeader, param.debugErr()))
+				}
+				continue
 			}
 
-			value, ok := contextValues[param.alias]
-			if !ok {
+			var err error
+			if param.typ.Kind() == reflect.Slice && param.typ.Elem().Kind() != reflect.Uint8 {
+				// multi-value headers may be sent as several lines or as a comma separated list
+				var parts []string
+				for _, value := range values {
+					for _, part := range strings.Split(value, ",") {
+						if part = strings.TrimSpace(part); part != "" {
+							parts = append(parts, part)
+						}
+					}
+				}
+				err = param.set(field, parts)
+			} else {
+				err = param.set(field, values)
+			}
+			if err != nil {
+				fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_HEADERS, param.alias, values, err, param.debugErr()))
+				continue
+			}
+			if param.constraints != nil {
+				if err := param.constraints.check(field); err != nil {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_HEADERS, param.alias, field.Interface(), err, param.debugErr()))
+				}
+			}
+		}
+		return fieldErrs
+	}
+
+	// cookies deserializer
+	cookiesUnmarshaler := func(dst reflect.Value, cookies []*http.Cookie) []FieldError {
+		v := sectionStruct(dst)
+		var fieldErrs []FieldError
+		for i := range cookiesFields {
+			param := &cookiesFields[i]
+			field := v.FieldByIndex(param.index)
+
+			var cookie *http.Cookie
+			for _, c := range cookies {
+				if c.Name == param.alias {
+					cookie = c
+					break
+				}
+			}
+			if cookie == nil {
+				if param.hasDefault() {
+					field.Set(param.defaultCopy())
+					continue
+				}
 				if !param.optional {
-					return nil, errors.Join(ErrMissingContextValue, debugErr)
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_COOKIES, param.alias, nil, ErrMissingCookie, param.debugErr()))
 				}
 				continue
 			}
-			if value == nil && !param.optional {
-				return nil, errors.Join(ErrMissingContextValue, debugErr)
+
+			// expose the full cookie
+			switch param.typ {
+			case cookiePtrType:
+				field.Set(reflect.ValueOf(cookie))
+				continue
+			case cookieType:
+				field.Set(reflect.ValueOf(*cookie))
+				continue
 			}
 
-			var unmarshaled any
-			if str, ok := value.(string); ok {
-				var err error
-				unmarshaled, err = unmarshalStrToType(param.typ, str)
-				if err != nil {
-					return nil, errors.Join(err, debugErr)
+			if err := param.set(field, []string{cookie.Value}); err != nil {
+				fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_COOKIES, param.alias, cookie.Value, err, param.debugErr()))
+				continue
+			}
+			if param.constraints != nil {
+				if err := param.constraints.check(field); err != nil {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_COOKIES, param.alias, field.Interface(), err, param.debugErr()))
 				}
-			} else {
-				unmarshaled = value
 			}
+		}
+		return fieldErrs
+	}
+
+	// url-encoded form deserializer
+	formUnmarshaler := func(dst reflect.Value, form url.Values) []FieldError {
+		v := sectionStruct(dst)
+		var fieldErrs []FieldError
+		for i := range formFields {
+			param := &formFields[i]
+			field := v.FieldByIndex(param.index)
 
-			// check type mismatch
-			if reflect.TypeOf(unmarshaled) != param.typ {
-				return nil, errors.Join(ErrTypeMismatch, debugErr,
-					fmt.Errorf("expected type %v, got %v", param.typ, reflect.TypeOf(unmarshaled)),
-				)
+			values := form[param.alias]
+			if len(values) == 0 || (param.omittable() && bind.NoValue(values)) {
+				if param.hasDefault() {
+					field.Set(param.defaultCopy())
+					continue
+				}
+				if !param.optional {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_FORM, param.alias, nil, ErrMissingFormField, param.debugErr()))
+				}
+				continue
 			}
 
-			field.Set(reflect.ValueOf(unmarshaled))
+			if err := param.set(field, values); err != nil {
+				fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_FORM, param.alias, values, err, param.debugErr()))
+				continue
+			}
+			if param.constraints != nil {
+				if err := param.constraints.check(field); err != nil {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_FORM, param.alias, field.Interface(), err, param.debugErr()))
+				}
+			}
 		}
-		// return v.Interface(), nil
-		if isPtr {
-			return v.Addr().Interface(), nil
+		return fieldErrs
+	}
+
+	// multipart form deserializer
+	multipartUnmarshaler := func(dst reflect.Value, multipartForm *multipart.Form) []FieldError {
+		v := sectionStruct(dst)
+		var fieldErrs []FieldError
+		if multipartForm == nil {
+			multipartForm = &multipart.Form{}
 		}
-		return v.Interface(), nil
+		for i := range multipartFields {
+			param := &multipartFields[i]
+			field := v.FieldByIndex(param.index)
+
+			// uploaded files
+			if param.typ == fileHeaderType || param.typ == fileHeadersType {
+				files := multipartForm.File[param.alias]
+				if len(files) == 0 {
+					if !param.optional {
+						fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_MULTIPART, param.alias, nil, ErrMissingFormField, param.debugErr()))
+					}
+					continue
+				}
+				if param.typ == fileHeaderType {
+					field.Set(reflect.ValueOf(files[0]))
+				} else {
+					field.Set(reflect.ValueOf(files))
+				}
+				continue
+			}
+
+			values := multipartForm.Value[param.alias]
+			if len(values) == 0 || (param.omittable() && bind.NoValue(values)) {
+				if param.hasDefault() {
+					field.Set(param.defaultCopy())
+					continue
+				}
+				if !param.optional {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_MULTIPART, param.alias, nil, ErrMissingFormField, param.debugErr()))
+				}
+				continue
+			}
+
+			if err := param.set(field, values); err != nil {
+				fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_MULTIPART, param.alias, values, err, param.debugErr()))
+				continue
+			}
+			if param.constraints != nil {
+				if err := param.constraints.check(field); err != nil {
+					fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_MULTIPART, param.alias, field.Interface(), err, param.debugErr()))
+				}
+			}
+		}
+		return fieldErrs
 	}
 
 	// return the unmarshaler
@@ -183,171 +396,333 @@ func BuildUnmarshaler[T any](reflected reflectedReq) unmarshaler[T] {
 		pathParams map[string]string,
 		queryParams map[string][]string,
 		contextValues map[string]any,
+		headers http.Header,
+		cookies []*http.Cookie,
+		form url.Values,
+		multipartForm *multipart.Form,
+		bodyCodec Codec,
 	) (T, error) {
-		req := reflect.New(reflected.typ).Elem()
+		reqPtr := reflect.New(reqType)
+		req := reqPtr.Elem()
 
-		// unmarshal json body
-		if reflected.hasJSONBody() {
-			jsonBody, err := jsonBodyUnmarshaler(body)
-			if err != nil {
-				return req.Interface().(T), err
-			}
-			field := req.FieldByName(reflected.jsonBodyFieldName)
-			if field.IsValid() {
-				field.Set(reflect.ValueOf(jsonBody).Elem())
-			}
+		// all the binding errors are collected and returned together
+		var fieldErrs []FieldError
+
+		if jsonBodyIndex >= 0 {
+			fieldErrs = append(fieldErrs, bodyUnmarshaler(req.Field(jsonBodyIndex), body, JSONCodec{})...)
+		}
+		if bodyIndex >= 0 {
+			fieldErrs = append(fieldErrs, bodyUnmarshaler(req.Field(bodyIndex), body, bodyCodec)...)
+		}
+		if pathParamsIndex >= 0 {
+			fieldErrs = append(fieldErrs, pathParamsUnmarshaler(req.Field(pathParamsIndex), pathParams)...)
+		}
+		if queryParamsIndex >= 0 {
+			fieldErrs = append(fieldErrs, queryParamsUnmarshaler(req.Field(queryParamsIndex), queryParams)...)
+		}
+		if contextValuesIndex >= 0 {
+			fieldErrs = append(fieldErrs, contextValuesUnmarshaler(req.Field(contextValuesIndex), contextValues)...)
+		}
+		if headersIndex >= 0 {
+			fieldErrs = append(fieldErrs, headersUnmarshaler(req.Field(headersIndex), headers)...)
+		}
+		if cookiesIndex >= 0 {
+			fieldErrs = append(fieldErrs, cookiesUnmarshaler(req.Field(cookiesIndex), cookies)...)
+		}
+		if formIndex >= 0 {
+			fieldErrs = append(fieldErrs, formUnmarshaler(req.Field(formIndex), form)...)
+		}
+		if multipartIndex >= 0 {
+			fieldErrs = append(fieldErrs, multipartUnmarshaler(req.Field(multipartIndex), multipartForm)...)
 		}
 
-		// unmarshal path params
-		if reflected.hasPathParams() {
-			pathParamsStruct, err := pathParamsUnmarshaler(pathParams)
-			if err != nil {
-				return req.Interface().(T), err
-			}
-			field := req.FieldByName(reflected.pathParamsFieldName)
-			if field.IsValid() {
-				field.Set(reflect.ValueOf(pathParamsStruct))
-			}
+		var result T
+		if isPtr {
+			result = reqPtr.Interface().(T)
+		} else {
+			result = req.Interface().(T)
 		}
+		if len(fieldErrs) > 0 {
+			return result, ValidationErrors{Errors: fieldErrs}
+		}
+		return result, nil
+	}
+}
 
-		// unmarshal query params
-		if reflected.hasQueryParams() {
-			queryParamsStruct, err := queryParamsUnmarshaler(queryParams)
-			if err != nil {
-				return req.Interface().(T), err
-			}
-			field := req.FieldByName(reflected.queryParamsFieldName)
-			if field.IsValid() {
-				field.Set(reflect.ValueOf(queryParamsStruct))
-			}
+// the struct of the section, allocated if the section is a pointer
+func sectionStruct(dst reflect.Value) reflect.Value {
+	if dst.Kind() == reflect.Ptr {
+		dst.Set(reflect.New(dst.Type().Elem()))
+		return dst.Elem()
+	}
+	return dst
+}
+
+// check the constraints of the decoded body fields
+func checkBodyConstraints(fields []reflectedKeyVal, body reflect.Value) []FieldError {
+	var fieldErrs []FieldError
+	for body.Kind() == reflect.Ptr {
+		if body.IsNil() {
+			return nil
+		}
+		body = body.Elem()
+	}
+	for i := range fields {
+		param := &fields[i]
+		// the promoted fields of nil embedded pointers are skipped
+		field, err := body.FieldByIndexErr(param.index)
+		if err != nil {
+			continue
+		}
+		if err := param.constraints.check(field); err != nil {
+			fieldErrs = append(fieldErrs, newFieldError(_EZAPI_TAG_BODY, param.alias, field.Interface(), err, param.debugErr()))
 		}
+	}
+	return fieldErrs
+}
 
-		// unmarshal context values
-		if reflected.hasContextValues() {
-			contextValuesStruct, err := contextValuesUnmarshaler(contextValues)
-			if err != nil {
-				return req.Interface().(T), err
-			}
-			field := req.FieldByName(reflected.contextValuesName)
-			if field.IsValid() {
-				field.Set(reflect.ValueOf(contextValuesStruct))
-			}
+var (
+	cookieType      = reflect.TypeOf(http.Cookie{})
+	cookiePtrType   = reflect.TypeOf(&http.Cookie{})
+	fileHeaderType  = reflect.TypeOf(&multipart.FileHeader{})
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader{})
+
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	uuidType            = reflect.TypeOf(uuid.UUID{})
+)
+
+// converts a single raw value to the field type
+type strConverter func(s string) (any, error)
+
+// sets a single raw value to the field
+type strSetter func(dst reflect.Value, s string) error
+
+// sets the raw values to the field: all of them into a slice, the first one otherwise
+type valuesSetter func(dst reflect.Value, values []string) error
+
+// helper function to compile the setter of the field type once, at startup.
+// Returns ErrorUnsuppType if the values can't be converted to the type
+func compileValuesSetter(typ reflect.Type) (valuesSetter, error) {
+	if typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8 {
+		setElem, err := compileStrSetter(typ.Elem())
+		if err != nil {
+			return nil, err
 		}
-		if reflected.isPtr {
-			return req.Addr().Interface().(T), nil
+		return func(dst reflect.Value, values []string) error {
+			slice := reflect.MakeSlice(typ, len(values), len(values))
+			for i, value := range values {
+				if err := setElem(slice.Index(i), value); err != nil {
+					return err
+				}
+			}
+			dst.Set(slice)
+			return nil
+		}, nil
+	}
+
+	set, err := compileStrSetter(typ)
+	if err != nil {
+		return nil, err
+	}
+	return func(dst reflect.Value, values []string) error {
+		return set(dst, values[0])
+	}, nil
+}
+
+func compileStrSetter(typ reflect.Type) (strSetter, error) {
+	convert, err := compileStrConverter(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	// the basic kinds are parsed right into the field, without boxing the value
+	if typ != durationType && !reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		switch typ.Kind() {
+		case reflect.String:
+			return func(dst reflect.Value, s string) error {
+				dst.SetString(s)
+				return nil
+			}, nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			bits := typ.Bits()
+			return func(dst reflect.Value, s string) error {
+				n, err := strconv.ParseInt(s, 10, bits)
+				if err != nil {
+					return err
+				}
+				dst.SetInt(n)
+				return nil
+			}, nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			bits := typ.Bits()
+			return func(dst reflect.Value, s string) error {
+				n, err := strconv.ParseUint(s, 10, bits)
+				if err != nil {
+					return err
+				}
+				dst.SetUint(n)
+				return nil
+			}, nil
+		case reflect.Float32, reflect.Float64:
+			bits := typ.Bits()
+			return func(dst reflect.Value, s string) error {
+				n, err := strconv.ParseFloat(s, bits)
+				if err != nil {
+					return err
+				}
+				dst.SetFloat(n)
+				return nil
+			}, nil
+		case reflect.Bool:
+			return func(dst reflect.Value, s string) error {
+				b, err := strconv.ParseBool(s)
+				if err != nil {
+					return err
+				}
+				dst.SetBool(b)
+				return nil
+			}, nil
 		}
-		return req.Interface().(T), nil
 	}
+
+	return func(dst reflect.Value, s string) error {
+		v, err := convert(s)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(v))
+		return nil
+	}, nil
 }
 
-func unmarshalSliceToType(typ reflect.Type, s []string) (any, error) {
-	switch typ.Kind() {
-	// SLICES
-	case reflect.Slice:
-		// Parse any other slice type
-		newSlice := reflect.MakeSlice(typ, 0, 0)
-		for _, part := range s {
-			v, err := unmarshalStrToType(typ.Elem(), part)
+func compileStrConverter(typ reflect.Type) (strConverter, error) {
+	// well-known types, checked before the kinds they are built on
+	switch typ {
+	case durationType:
+		return func(s string) (any, error) {
+			return time.ParseDuration(s)
+		}, nil
+	case timeType:
+		return func(s string) (any, error) {
+			return time.Parse(time.RFC3339, s)
+		}, nil
+	case uuidType:
+		return func(s string) (any, error) {
+			return uuid.Parse(s)
+		}, nil
+	}
+
+	// check if the type implements encoding.TextUnmarshaler, e.g. net.IP
+	if typ.Kind() != reflect.Ptr && reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return func(s string) (any, error) {
+			v := reflect.New(typ)
+			err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
 			if err != nil {
 				return nil, err
 			}
-			newSlice = reflect.Append(newSlice, reflect.ValueOf(v))
-		}
-		return newSlice.Interface(), nil
-	default:
-		panic("unsupported type")
+			return v.Elem().Interface(), nil
+		}, nil
 	}
-}
 
-func unmarshalStrToType(typ reflect.Type, s string) (any, error) {
 	switch typ.Kind() {
 	// STRINGS
 	case reflect.String:
-		return s, nil
+		return func(s string) (any, error) {
+			return reflect.ValueOf(s).Convert(typ).Interface(), nil
+		}, nil
 	// INTS
-	case reflect.Int:
-		return strconv.Atoi(s)
-	case reflect.Int8:
-		return strconv.ParseInt(s, 10, 8)
-	case reflect.Int16:
-		return strconv.ParseInt(s, 10, 16)
-	case reflect.Int32:
-		return strconv.ParseInt(s, 10, 32)
-	case reflect.Int64:
-		return strconv.ParseInt(s, 10, 64)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := typ.Bits()
+		return func(s string) (any, error) {
+			n, err := strconv.ParseInt(s, 10, bits)
+			if err != nil {
+				return nil, err
+			}
+			return reflect.ValueOf(n).Convert(typ).Interface(), nil
+		}, nil
 	// UINTS
-	case reflect.Uint:
-		return strconv.ParseUint(s, 10, 0)
-	case reflect.Uint8:
-		return strconv.ParseUint(s, 10, 8)
-	case reflect.Uint16:
-		return strconv.ParseUint(s, 10, 16)
-	case reflect.Uint32:
-		return strconv.ParseUint(s, 10, 32)
-	case reflect.Uint64:
-		return strconv.ParseUint(s, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		bits := typ.Bits()
+		return func(s string) (any, error) {
+			n, err := strconv.ParseUint(s, 10, bits)
+			if err != nil {
+				return nil, err
+			}
+			return reflect.ValueOf(n).Convert(typ).Interface(), nil
+		}, nil
 	// FLOATS
-	case reflect.Float64:
-		return strconv.ParseFloat(s, 64)
-	case reflect.Float32:
-		return strconv.ParseFloat(s, 32)
+	case reflect.Float32, reflect.Float64:
+		bits := typ.Bits()
+		return func(s string) (any, error) {
+			n, err := strconv.ParseFloat(s, bits)
+			if err != nil {
+				return nil, err
+			}
+			return reflect.ValueOf(n).Convert(typ).Interface(), nil
+		}, nil
 	// BOOL
 	case reflect.Bool:
-		return strconv.ParseBool(s)
+		return func(s string) (any, error) {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return nil, err
+			}
+			return reflect.ValueOf(b).Convert(typ).Interface(), nil
+		}, nil
 	// BYTES
 	case reflect.Slice:
 		if typ.Elem().Kind() == reflect.Uint8 {
-			return []byte(s), nil
+			return func(s string) (any, error) {
+				return reflect.ValueOf([]byte(s)).Convert(typ).Interface(), nil
+			}, nil
 		}
-		panic("unsupported type")
 	// STRUCT
 	case reflect.Struct:
-		// check if the type implements encoding.TextUnmarshaler
-		if typ.Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) {
-			v := reflect.New(typ).Interface().(encoding.TextUnmarshaler)
-			err := v.UnmarshalText([]byte(s))
-			if err != nil {
-				return nil, err
-			}
-			return v, nil
-		}
-
 		// check if the type implements json.Unmarshaler
-		if typ.Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
-			v := reflect.New(typ).Interface().(json.Unmarshaler)
-			err := v.UnmarshalJSON([]byte(s))
-			if err != nil {
-				return nil, err
-			}
-			return v, nil
+		if reflect.PointerTo(typ).Implements(jsonUnmarshalerType) {
+			return func(s string) (any, error) {
+				v := reflect.New(typ)
+				err := v.Interface().(json.Unmarshaler).UnmarshalJSON([]byte(s))
+				if err != nil {
+					return nil, err
+				}
+				return v.Elem().Interface(), nil
+			}, nil
 		}
-
-		panic("unsupported type")
 	// POINTERS
 	case reflect.Ptr:
-		v, err := unmarshalStrToType(typ.Elem(), s)
+		convertElem, err := compileStrConverter(typ.Elem())
 		if err != nil {
 			return nil, err
 		}
-		// create new pointer and set the value
-		ptr := reflect.New(typ.Elem())
-		ptr.Elem().Set(reflect.ValueOf(v))
-		return ptr.Interface(), nil
-	default:
-		// parse UUID
-		if typ == reflect.TypeOf(uuid.UUID{}) {
-			return uuid.Parse(s)
-		}
-
-		panic("unsupported type")
+		return func(s string) (any, error) {
+			v, err := convertElem(s)
+			if err != nil {
+				return nil, err
+			}
+			// create new pointer and set the value
+			ptr := reflect.New(typ.Elem())
+			ptr.Elem().Set(reflect.ValueOf(v))
+			return ptr.Interface(), nil
+		}, nil
 	}
+
+	return nil, fmt.Errorf("%w: %s", ErrorUnsuppType, typ)
 }
 
 var (
-	ErrInvalidField        = errors.New("invalid field")
-	ErrMissingQueryParam   = errors.New("missing query param")
-	ErrMissingPathParam    = errors.New("missing path param")
-	ErrMissingContextValue = errors.New("missing context value")
-	ErrTypeMismatch        = errors.New("type mismatch")
-	ErrorUnsuppType        = errors.New("unsupported type")
+	ErrInvalidField         = errors.New("invalid field")
+	ErrMissingQueryParam    = errors.New("missing query param")
+	ErrMissingPathParam     = errors.New("missing path param")
+	ErrMissingContextValue  = errors.New("missing context value")
+	ErrMissingHeader        = errors.New("missing header")
+	ErrMissingCookie        = errors.New("missing cookie")
+	ErrMissingFormField     = errors.New("missing form field")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrConstraintViolation  = bind.ErrConstraintViolation
+	ErrTypeMismatch         = errors.New("type mismatch")
+	ErrorUnsuppType         = errors.New("unsupported type")
 )
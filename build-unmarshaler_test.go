@@ -0,0 +1,304 @@
+package ezapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// bind the request with H, the bound request is returned if the handler is called
+func bindRequest[T any](t *testing.T, r *http.Request, opts ...HandlerOpt) (T, *httptest.ResponseRecorder) {
+	t.Helper()
+	var bound T
+	handler := H(func(ctx Context[T]) (string, RespError) {
+		bound = ctx.GetReq()
+		return "ok", nil
+	}, opts...)
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return bound, w
+}
+
+// the locations of the field errors of the rendered binding failure
+func failedLocations(t *testing.T, w *httptest.ResponseRecorder) []string {
+	t.Helper()
+	var body EzAPIError
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected an error body, got %q: %v", w.Body, err)
+	}
+	locations := make([]string, len(body.Errors))
+	for i, fieldErr := range body.Errors {
+		locations[i] = fieldErr.Location
+	}
+	return locations
+}
+
+type defaultPtrReq struct {
+	Query struct {
+		Limit *int   `ezapi:"limit,default=20"`
+		Tags  []*int `ezapi:"tags,default=1|2"`
+	} `ezapi:"query"`
+}
+
+func TestDefaultCopyPointers(t *testing.T) {
+	unmarshal := BuildUnmarshaler[defaultPtrReq](ReflectReq[defaultPtrReq]())
+	bind := func() defaultPtrReq {
+		req, err := unmarshal(nil, nil, map[string][]string{}, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	first := bind()
+	*first.Query.Limit = 99
+	*first.Query.Tags[0] = 99
+
+	second := bind()
+	if *second.Query.Limit != 20 {
+		t.Errorf("limit default changed by the handler: %d", *second.Query.Limit)
+	}
+	if *second.Query.Tags[0] != 1 {
+		t.Errorf("tags default changed by the handler: %d", *second.Query.Tags[0])
+	}
+}
+
+type emptyValueReq struct {
+	Query struct {
+		Limit  int  `ezapi:"limit,default=20"`
+		Offset *int `ezapi:"offset,optional"`
+		Page   int  `ezapi:"page"`
+	} `ezapi:"query"`
+}
+
+func TestEmptyValueIsMissing(t *testing.T) {
+	unmarshal := BuildUnmarshaler[emptyValueReq](ReflectReq[emptyValueReq]())
+	req, err := unmarshal(nil, nil, map[string][]string{
+		"limit":  {""},
+		"offset": {""},
+		"page":   {"1"},
+	}, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Query.Limit != 20 {
+		t.Errorf("expected the default limit, got %d", req.Query.Limit)
+	}
+	if req.Query.Offset != nil {
+		t.Errorf("expected no offset, got %d", *req.Query.Offset)
+	}
+
+	// the empty value of the required params is still bound
+	if _, err := unmarshal(nil, nil, map[string][]string{"page": {""}}, nil, nil, nil, nil, nil, nil); err == nil {
+		t.Error("expected the empty required page to fail")
+	}
+}
+
+type headerReq struct {
+	Headers struct {
+		RequestID string   `ezapi:"x-request-id"`
+		Tenant    int      `ezapi:"X-Tenant,optional"`
+		IfMatch   []string `ezapi:"If-Match"`
+	} `ezapi:"header"`
+}
+
+func TestHeaderBinding(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-Id", "req-1")
+	r.Header.Add("If-Match", `"a", "b"`)
+	r.Header.Add("If-Match", `"c"`)
+	req, w := bindRequest[headerReq](t, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	// the aliases are matched with the canonical header names
+	if req.Headers.RequestID != "req-1" {
+		t.Errorf("expected the request id, got %q", req.Headers.RequestID)
+	}
+	if req.Headers.Tenant != 0 {
+		t.Errorf("expected no tenant, got %d", req.Headers.Tenant)
+	}
+	// the lines and the comma separated values are all bound
+	if got := req.Headers.IfMatch; len(got) != 3 || got[0] != `"a"` || got[1] != `"b"` || got[2] != `"c"` {
+		t.Errorf("expected every If-Match value, got %q", got)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Tenant", "x")
+	_, w = bindRequest[headerReq](t, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body)
+	}
+	want := []string{"header.x-request-id", "header.X-Tenant", "header.If-Match"}
+	if got := failedLocations(t, w); !slices.Equal(got, want) {
+		t.Errorf("expected the failed headers %q, got %q", want, got)
+	}
+}
+
+type cookieReq struct {
+	Cookies struct {
+		Session *http.Cookie `ezapi:"session"`
+		Theme   http.Cookie  `ezapi:"theme,optional"`
+		Visits  int          `ezapi:"visits,optional"`
+	} `ezapi:"cookie"`
+}
+
+func TestCookieBinding(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session", Value: "s-1"})
+	r.AddCookie(&http.Cookie{Name: "visits", Value: "3"})
+	req, w := bindRequest[cookieReq](t, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	if req.Cookies.Session == nil || req.Cookies.Session.Name != "session" || req.Cookies.Session.Value != "s-1" {
+		t.Errorf("expected the full session cookie, got %+v", req.Cookies.Session)
+	}
+	if req.Cookies.Theme.Name != "" {
+		t.Errorf("expected no theme cookie, got %+v", req.Cookies.Theme)
+	}
+	if req.Cookies.Visits != 3 {
+		t.Errorf("expected 3 visits, got %d", req.Cookies.Visits)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "visits", Value: "many"})
+	_, w = bindRequest[cookieReq](t, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body)
+	}
+	want := []string{"cookie.session", "cookie.visits"}
+	if got := failedLocations(t, w); !slices.Equal(got, want) {
+		t.Errorf("expected the failed cookies %q, got %q", want, got)
+	}
+}
+
+type formReq struct {
+	Form struct {
+		Name string   `ezapi:"name"`
+		Age  int      `ezapi:"age,optional"`
+		Tags []string `ezapi:"tag,optional"`
+	} `ezapi:"form"`
+}
+
+func TestFormBinding(t *testing.T) {
+	body := strings.NewReader("name=Ada&age=36&tag=a&tag=b")
+	r := httptest.NewRequest(http.MethodPost, "/", body)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req, w := bindRequest[formReq](t, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	if req.Form.Name != "Ada" || req.Form.Age != 36 || !slices.Equal(req.Form.Tags, []string{"a", "b"}) {
+		t.Errorf("expected the form fields, got %+v", req.Form)
+	}
+
+	// only the body is read, the query params of the same names are not bound
+	r = httptest.NewRequest(http.MethodPost, "/?name=Ada&age=36", strings.NewReader("age=x"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	_, w = bindRequest[formReq](t, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body)
+	}
+	want := []string{"form.name", "form.age"}
+	if got := failedLocations(t, w); !slices.Equal(got, want) {
+		t.Errorf("expected the failed form fields %q, got %q", want, got)
+	}
+}
+
+type multipartReq struct {
+	Multipart struct {
+		Title  string                  `ezapi:"title"`
+		Avatar *multipart.FileHeader   `ezapi:"avatar"`
+		Extras []*multipart.FileHeader `ezapi:"extra,optional"`
+	} `ezapi:"multipart"`
+}
+
+// the multipart request with the title, the avatar and the extra files
+func newMultipartRequest(t *testing.T, title string, files map[string][]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if title != "" {
+		mw.WriteField("title", title)
+	}
+	for field, contents := range files {
+		for i, content := range contents {
+			fw, err := mw.CreateFormFile(field, fmt.Sprintf("%s-%d.txt", field, i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			fw.Write([]byte(content))
+		}
+	}
+	mw.Close()
+	r := httptest.NewRequest(http.MethodPost, "/", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	t.Cleanup(func() {
+		if r.MultipartForm != nil {
+			r.MultipartForm.RemoveAll()
+		}
+	})
+	return r
+}
+
+func TestMultipartBinding(t *testing.T) {
+	r := newMultipartRequest(t, "hello", map[string][]string{
+		"avatar": {"png"},
+		"extra":  {"a", "b"},
+	})
+	req, w := bindRequest[multipartReq](t, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	if req.Multipart.Title != "hello" {
+		t.Errorf("expected the title, got %q", req.Multipart.Title)
+	}
+	if req.Multipart.Avatar == nil || req.Multipart.Avatar.Size != 3 {
+		t.Errorf("expected the avatar file, got %+v", req.Multipart.Avatar)
+	}
+	if len(req.Multipart.Extras) != 2 {
+		t.Errorf("expected the extra files, got %d", len(req.Multipart.Extras))
+	}
+
+	_, w = bindRequest[multipartReq](t, newMultipartRequest(t, "", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body)
+	}
+	want := []string{"multipart.title", "multipart.avatar"}
+	if got := failedLocations(t, w); !slices.Equal(got, want) {
+		t.Errorf("expected the failed multipart fields %q, got %q", want, got)
+	}
+}
+
+func TestMultipartMaxMemory(t *testing.T) {
+	// reports whether the uploaded avatar was stored on disk
+	onDisk := func(opts ...HandlerOpt) bool {
+		r := newMultipartRequest(t, "hello", map[string][]string{"avatar": {"png"}})
+		req, w := bindRequest[multipartReq](t, r, opts...)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+		}
+		f, err := req.Multipart.Avatar.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		_, isFile := f.(*os.File)
+		return isFile
+	}
+
+	if onDisk() {
+		t.Error("expected the small file in memory by default")
+	}
+	if !onDisk(MultipartMaxMemory(1)) {
+		t.Error("expected the file over the max memory on disk")
+	}
+}
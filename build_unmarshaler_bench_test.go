@@ -0,0 +1,120 @@
+package ezapi_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ic-it/ezapi"
+	"github.com/ic-it/ezapi/examples/todo"
+	"github.com/ic-it/ezapi/examples/todo/api"
+)
+
+// The routes of the todo-server example served by H, the requests are bound
+// by reflection even though the example has generated binders, e.g.
+//
+//	go test -run '^$' -bench BenchmarkTodo -benchmem
+
+var benchTodoID = uuid.MustParse("7f1c2a3e-52d1-4c0b-9d2e-3b8f6a1c9e10")
+
+// serve the request made by newReq on every iteration and check the status
+func benchmarkRoute[T any, U any](b *testing.B, pattern string, status int,
+	handler func(ezapi.Context[T]) (U, ezapi.RespError), newReq func() *http.Request, opts ...ezapi.HandlerOpt) {
+	// the example request types log in their validators
+	logOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(logOutput) })
+	ezapi.UnregisterBinder[T]()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(pattern, ezapi.H(handler, opts...))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, newReq())
+		if w.Code != status {
+			b.Fatalf("expected %d, got %d: %s", status, w.Code, w.Body)
+		}
+	}
+}
+
+func newJSONRequest(method, target, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func BenchmarkTodoCreate(b *testing.B) {
+	benchmarkRoute(b, "POST /todo", http.StatusCreated,
+		func(ctx ezapi.Context[api.CreateTodoReq]) (ezapi.Response[todo.TodoIDOnly], ezapi.RespError) {
+			return ezapi.Created("/todo/"+benchTodoID.String(), todo.TodoIDOnly{ID: benchTodoID}), nil
+		},
+		func() *http.Request {
+			return newJSONRequest(http.MethodPost, "/todo", `{"title":"buy milk","description":"two bottles"}`)
+		},
+		ezapi.SuccessStatus(http.StatusCreated),
+	)
+}
+
+func BenchmarkTodoGet(b *testing.B) {
+	benchmarkRoute(b, "GET /todo/{id}", http.StatusOK,
+		func(ctx ezapi.Context[api.GetTodoReq]) (*todo.Todo, ezapi.RespError) {
+			return &todo.Todo{
+				TodoIDOnly: todo.TodoIDOnly{ID: ctx.GetReq().PathParams.ID},
+				BaseTodo:   todo.BaseTodo{Title: "buy milk"},
+			}, nil
+		},
+		func() *http.Request { return httptest.NewRequest(http.MethodGet, "/todo/"+benchTodoID.String(), nil) },
+	)
+}
+
+func BenchmarkTodoGetAll(b *testing.B) {
+	benchmarkRoute(b, "GET /todos", http.StatusOK,
+		func(ctx ezapi.Context[api.GetAllTodosReq]) (api.GetAllTodosRep, ezapi.RespError) {
+			return api.GetAllTodosRep{Todos: []todo.Todo{{BaseTodo: todo.BaseTodo{Title: ctx.GetReq().QueryParams.Title}}}}, nil
+		},
+		func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "/todos?title=milk&description=two", nil)
+		},
+	)
+}
+
+func BenchmarkTodoUpdate(b *testing.B) {
+	benchmarkRoute(b, "PUT /todo/{id}", http.StatusOK,
+		func(ctx ezapi.Context[api.UpdateTodoReq]) (*todo.TodoIDOnly, ezapi.RespError) {
+			return &todo.TodoIDOnly{ID: ctx.GetReq().PathParams.ID}, nil
+		},
+		func() *http.Request {
+			return newJSONRequest(http.MethodPut, "/todo/"+benchTodoID.String(), `{"newTitle":"buy oat milk"}`)
+		},
+	)
+}
+
+func BenchmarkTodoDelete(b *testing.B) {
+	benchmarkRoute(b, "DELETE /todo/{id}", http.StatusOK,
+		func(ctx ezapi.Context[api.DeleteTodoReq]) (*todo.TodoIDOnly, ezapi.RespError) {
+			return &todo.TodoIDOnly{ID: ctx.GetReq().PathParams.ID}, nil
+		},
+		func() *http.Request {
+			return httptest.NewRequest(http.MethodDelete, "/todo/"+benchTodoID.String(), nil)
+		},
+	)
+}
+
+func BenchmarkTodoHello(b *testing.B) {
+	benchmarkRoute(b, "GET /hello/{name}", http.StatusOK,
+		func(ctx ezapi.Context[*api.HelloReq]) (api.HelloRep, ezapi.RespError) {
+			return api.HelloRep{Message: "Hello, " + ctx.GetReq().PathParams.Name + "!"}, nil
+		},
+		func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/hello/Ada?name=Grace", nil)
+			return r.WithContext(context.WithValue(r.Context(), "names", []string{"Alice", "Bob"}))
+		},
+	)
+}
@@ -0,0 +1,519 @@
+package ezapi
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Client calls the ezapi handlers over HTTP with the same request and
+// response types the server uses, see Call
+type Client struct {
+	// Base URL of the server, e.g. "http://localhost:8080"
+	BaseURL string
+	// http.DefaultClient if nil
+	HTTPClient *http.Client
+	// Sent with every request, e.g. Authorization
+	Header http.Header
+	// Codecs of the bodies, they take precedence over the global ones.
+	// The first one encodes the `body` sections
+	Codecs []Codec
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL}
+}
+
+// Call sends the request to the route and decodes the response body into U.
+// U may be a Response to get the status and the headers too.
+// Responses with a non-2xx status are returned as a *ResponseError
+func Call[T any, U any](c *Client, route Route, req T) (U, error) {
+	return CallContext[T, U](context.Background(), c, route, req)
+}
+
+// CallContext is Call with a context
+func CallContext[T any, U any](ctx context.Context, c *Client, route Route, req T) (U, error) {
+	var result U
+	httpReq, err := NewRequest(ctx, c, route, req)
+	if err != nil {
+		return result, err
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return result, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, &ResponseError{
+			Status: resp.StatusCode,
+			Header: resp.Header,
+			Body:   body,
+			codecs: c.codecs(),
+		}
+	}
+
+	decode := func(v any) error {
+		return decodeBody(c.codecs(), resp.Header.Get("Content-Type"), body, v)
+	}
+	if envelope, ok := any(&result).(envelopeDecoder); ok {
+		return result, envelope.decodeEnvelope(resp.StatusCode, resp.Header, decode)
+	}
+	return result, decode(&result)
+}
+
+// NewRequest builds the HTTP request of the route from the request struct:
+// the path params fill the pattern, the query params, headers and cookies are
+// set and the body section is encoded. Context values are left to the server.
+// The pointer params are sent whenever they are non-nil, even to a zero value
+// like false or 0. The optional params of the value types are not sent when
+// they are zero, so the server applies their defaults: they can't override a
+// default with the zero value, use a pointer for that
+func NewRequest[T any](ctx context.Context, c *Client, route Route, req T) (*http.Request, error) {
+	reflected, err := clientReflectReq[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(&req).Elem()
+	if reflected.isPtr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("%w: nil request", ErrInvalidRequest)
+		}
+		v = v.Elem()
+	}
+
+	// path
+	path := route.Pattern
+	if section, ok := clientSection(v, reflected.pathParamsFieldName); ok {
+		values := map[string]string{}
+		for i := range reflected.pathParams {
+			param := &reflected.pathParams[i]
+			encoded, err := encodeParam(param, section.FieldByIndex(param.index))
+			if err != nil {
+				return nil, newFieldError(_EZAPI_TAG_PATH_PARAMS, param.alias, nil, err, nil)
+			}
+			if len(encoded) > 0 {
+				values[param.alias] = encoded[0]
+			}
+		}
+		if path, err = fillPattern(path, values); err != nil {
+			return nil, err
+		}
+	} else if path, err = fillPattern(path, nil); err != nil {
+		return nil, err
+	}
+
+	// query
+	query := url.Values{}
+	if section, ok := clientSection(v, reflected.queryParamsFieldName); ok {
+		if err := encodeParams(_EZAPI_TAG_QUERY_PARAMS, reflected.queryParams, section, query.Add); err != nil {
+			return nil, err
+		}
+	}
+
+	// body
+	var body io.Reader
+	var contentType string
+	switch {
+	case reflected.jsonBodyType != nil:
+		encoded, err := json.Marshal(v.FieldByName(reflected.jsonBodyFieldName).Interface())
+		if err != nil {
+			return nil, err
+		}
+		body, contentType = bytes.NewReader(encoded), "application/json"
+	case reflected.bodyType != nil:
+		codec := c.codecs()[0]
+		var buf bytes.Buffer
+		if err := codec.Encode(&buf, v.FieldByName(reflected.bodyFieldName).Interface()); err != nil {
+			return nil, err
+		}
+		body, contentType = &buf, codec.MediaTypes()[0]
+	case reflected.formType != nil:
+		form := url.Values{}
+		if section, ok := clientSection(v, reflected.formFieldName); ok {
+			if err := encodeParams(_EZAPI_TAG_FORM, reflected.form, section, form.Add); err != nil {
+				return nil, err
+			}
+		}
+		body, contentType = strings.NewReader(form.Encode()), "application/x-www-form-urlencoded"
+	case reflected.multipartType != nil:
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		if section, ok := clientSection(v, reflected.multipartFieldName); ok {
+			if err := encodeMultipart(mw, reflected.multipart, section); err != nil {
+				return nil, err
+			}
+		}
+		if err := mw.Close(); err != nil {
+			return nil, err
+		}
+		body, contentType = &buf, mw.FormDataContentType()
+	}
+
+	target := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, route.Method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.Header {
+		httpReq.Header[key] = append([]string(nil), values...)
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if httpReq.Header.Get("Accept") == "" {
+		httpReq.Header.Set("Accept", c.codecs()[0].MediaTypes()[0])
+	}
+
+	// headers
+	if section, ok := clientSection(v, reflected.headersFieldName); ok {
+		if err := encodeParams(_EZAPI_TAG_HEADERS, reflected.headers, section, httpReq.Header.Add); err != nil {
+			return nil, err
+		}
+	}
+
+	// cookies
+	if section, ok := clientSection(v, reflected.cookiesFieldName); ok {
+		for i := range reflected.cookies {
+			param := &reflected.cookies[i]
+			field := section.FieldByIndex(param.index)
+
+			// full cookies are sent as is, under the param name
+			switch param.typ {
+			case cookiePtrType:
+				if !field.IsNil() {
+					cookie := *field.Interface().(*http.Cookie)
+					cookie.Name = param.alias
+					httpReq.AddCookie(&cookie)
+				}
+				continue
+			case cookieType:
+				cookie := field.Interface().(http.Cookie)
+				if cookie.Value != "" || !param.optional {
+					cookie.Name = param.alias
+					httpReq.AddCookie(&cookie)
+				}
+				continue
+			}
+
+			encoded, err := encodeParam(param, field)
+			if err != nil {
+				return nil, newFieldError(_EZAPI_TAG_COOKIES, param.alias, nil, err, nil)
+			}
+			if len(encoded) > 0 {
+				httpReq.AddCookie(&http.Cookie{Name: param.alias, Value: encoded[0]})
+			}
+		}
+	}
+	return httpReq, nil
+}
+
+// ResponseError is a response with a non-2xx status returned by Call
+type ResponseError struct {
+	Status int
+	Header http.Header
+	Body   []byte
+
+	codecs []Codec
+}
+
+func (e *ResponseError) Error() string {
+	// the message of the default error bodies
+	var ezErr EzAPIError
+	if err := e.Decode(&ezErr); err == nil && ezErr.Message != "" {
+		return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), ezErr.Message)
+	}
+	var problem Problem
+	if err := e.Decode(&problem); err == nil && problem.Detail != "" {
+		return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), problem.Detail)
+	}
+	if body := strings.TrimSpace(string(e.Body)); body != "" {
+		return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), body)
+	}
+	return fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+}
+
+// Decode decodes the error body with the codec of its Content-Type
+func (e *ResponseError) Decode(v any) error {
+	return decodeBody(e.codecs, e.Header.Get("Content-Type"), e.Body, v)
+}
+
+// ErrorBody decodes the body of the *ResponseError in err into E,
+// e.g. ErrorBody[EzAPIError](err). False if err is not a *ResponseError
+// or its body isn't an E
+func ErrorBody[E any](err error) (E, bool) {
+	var body E
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) {
+		return body, false
+	}
+	if err := respErr.Decode(&body); err != nil {
+		return body, false
+	}
+	return body, true
+}
+
+// implemented by Response, filled with the status and the headers
+type envelopeDecoder interface {
+	decodeEnvelope(status int, header http.Header, decode func(any) error) error
+}
+
+// client codecs followed by the global ones
+func (c *Client) codecs() []Codec {
+	globalCodecsMu.RLock()
+	defer globalCodecsMu.RUnlock()
+	codecs := make([]Codec, 0, len(c.Codecs)+len(globalCodecs))
+	codecs = append(codecs, c.Codecs...)
+	codecs = append(codecs, globalCodecs...)
+	return codecs
+}
+
+// decode the body with the codec of the Content-Type, empty bodies are skipped
+func decodeBody(codecs []Codec, contentType string, body []byte, v any) error {
+	if len(body) == 0 {
+		return nil
+	}
+	codec := codecForContentType(codecs, contentType)
+	if codec == nil {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+	return codec.Decode(bytes.NewReader(body), v)
+}
+
+var clientReqs sync.Map // reflect.Type -> reflectedReq
+
+// the request struct is reflected once per type
+func clientReflectReq[T any]() (reflectedReq, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if cached, ok := clientReqs.Load(t); ok {
+		return cached.(reflectedReq), nil
+	}
+	reflected, err := reflectReq[T]()
+	if err != nil {
+		return reflectedReq{}, err
+	}
+	clientReqs.Store(t, reflected)
+	return reflected, nil
+}
+
+// the struct of the section, false if the request has no such section or it is nil
+func clientSection(req reflect.Value, fieldName string) (reflect.Value, bool) {
+	if fieldName == "" {
+		return reflect.Value{}, false
+	}
+	section := req.FieldByName(fieldName)
+	if section.Kind() == reflect.Ptr {
+		if section.IsNil() {
+			return reflect.Value{}, false
+		}
+		section = section.Elem()
+	}
+	return section, true
+}
+
+// encode the params of the section with the add function, e.g. url.Values.Add
+func encodeParams(section string, params []reflectedKeyVal, v reflect.Value, add func(key, value string)) error {
+	for i := range params {
+		param := &params[i]
+		encoded, err := encodeParam(param, v.FieldByIndex(param.index))
+		if err != nil {
+			return newFieldError(section, param.alias, nil, err, nil)
+		}
+		for _, value := range encoded {
+			add(param.alias, value)
+		}
+	}
+	return nil
+}
+
+// encode the values and the files of the multipart form
+func encodeMultipart(mw *multipart.Writer, params []reflectedKeyVal, v reflect.Value) error {
+	for i := range params {
+		param := &params[i]
+		field := v.FieldByIndex(param.index)
+
+		// uploaded files are copied from their headers
+		var files []*multipart.FileHeader
+		switch param.typ {
+		case fileHeaderType:
+			if !field.IsNil() {
+				files = append(files, field.Interface().(*multipart.FileHeader))
+			}
+		case fileHeadersType:
+			files = field.Interface().([]*multipart.FileHeader)
+		default:
+			encoded, err := encodeParam(param, field)
+			if err != nil {
+				return newFieldError(_EZAPI_TAG_MULTIPART, param.alias, nil, err, nil)
+			}
+			for _, value := range encoded {
+				if err := mw.WriteField(param.alias, value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		for _, file := range files {
+			if err := copyFormFile(mw, param.alias, file); err != nil {
+				return newFieldError(_EZAPI_TAG_MULTIPART, param.alias, file.Filename, err, nil)
+			}
+		}
+	}
+	return nil
+}
+
+func copyFormFile(mw *multipart.Writer, name string, file *multipart.FileHeader) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := mw.CreateFormFile(name, file.Filename)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+// encode the param to its raw values: one per item for the slices, none for
+// the nil pointers and the zero values of the optional params. The non-nil
+// pointers are always encoded, so they can send the zero values
+func encodeParam(param *reflectedKeyVal, field reflect.Value) ([]string, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	} else if param.optional && field.IsZero() {
+		return nil, nil
+	}
+
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		values := make([]string, 0, field.Len())
+		for i := 0; i < field.Len(); i++ {
+			value, err := encodeValue(field.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	value, err := encodeValue(field)
+	if err != nil {
+		return nil, err
+	}
+	return []string{value}, nil
+}
+
+// encode a single value the way compileStrConverter decodes it
+func encodeValue(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == durationType {
+		return v.Interface().(fmt.Stringer).String(), nil
+	}
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+	if reflect.PointerTo(v.Type()).Implements(textMarshalerType) {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		text, err := ptr.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+	}
+	return "", errors.Join(ErrorUnsuppType, fmt.Errorf("can't encode %s", v.Type()))
+}
+
+// fill the wildcards of the http.ServeMux pattern with the escaped values
+func fillPattern(pattern string, values map[string]string) (string, error) {
+	var path strings.Builder
+	for {
+		start := strings.IndexByte(pattern, '{')
+		if start < 0 {
+			path.WriteString(pattern)
+			return path.String(), nil
+		}
+		end := strings.IndexByte(pattern[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unclosed wildcard in %q", ErrInvalidRequest, pattern)
+		}
+		end += start
+		path.WriteString(pattern[:start])
+
+		name := pattern[start+1 : end]
+		pattern = pattern[end+1:]
+		if name == "$" {
+			continue
+		}
+		name, rest := strings.CutSuffix(name, "...")
+		value, ok := values[name]
+		if !ok || value == "" {
+			return "", newFieldError(_EZAPI_TAG_PATH_PARAMS, name, nil, ErrMissingPathParam, nil)
+		}
+		if rest {
+			// the remaining segments keep their slashes
+			segments := strings.Split(value, "/")
+			for i, segment := range segments {
+				segments[i] = url.PathEscape(segment)
+			}
+			path.WriteString(strings.Join(segments, "/"))
+		} else {
+			path.WriteString(url.PathEscape(value))
+		}
+	}
+}
+
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+)
@@ -0,0 +1,40 @@
+package ezapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type clientParamsReq struct {
+	Query struct {
+		Active *bool   `ezapi:"active,default=true"`
+		Limit  *int    `ezapi:"limit,default=20"`
+		Offset int     `ezapi:"offset,default=10"`
+		Name   *string `ezapi:"name,optional"`
+	} `ezapi:"query"`
+}
+
+func TestNewRequestZeroPointers(t *testing.T) {
+	var req clientParamsReq
+	active, limit := false, 0
+	req.Query.Active = &active
+	req.Query.Limit = &limit
+
+	route := Route{Method: http.MethodGet, Pattern: "/todo"}
+	httpReq, err := NewRequest(context.Background(), NewClient("http://localhost"), route, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := httpReq.URL.Query()
+	if got := query["active"]; len(got) != 1 || got[0] != "false" {
+		t.Errorf("expected active=false, got %v", got)
+	}
+	if got := query["limit"]; len(got) != 1 || got[0] != "0" {
+		t.Errorf("expected limit=0, got %v", got)
+	}
+	// the zero values can't be told from the missing ones
+	if query.Has("offset") || query.Has("name") {
+		t.Errorf("expected the zero value and the nil pointer to be skipped, got %v", query)
+	}
+}
@@ -0,0 +1,909 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"hash/fnv"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ic-it/ezapi"
+)
+
+// the sections in the order the runtime binds them
+var sectionOrder = []ezapi.Section{
+	ezapi.SectionJSONBody,
+	ezapi.SectionBody,
+	ezapi.SectionPath,
+	ezapi.SectionQuery,
+	ezapi.SectionContext,
+	ezapi.SectionHeader,
+	ezapi.SectionCookie,
+	ezapi.SectionForm,
+	ezapi.SectionMultipart,
+}
+
+var missingErrs = map[ezapi.Section]string{
+	ezapi.SectionPath:      "ezapi.ErrMissingPathParam",
+	ezapi.SectionQuery:     "ezapi.ErrMissingQueryParam",
+	ezapi.SectionContext:   "ezapi.ErrMissingContextValue",
+	ezapi.SectionHeader:    "ezapi.ErrMissingHeader",
+	ezapi.SectionCookie:    "ezapi.ErrMissingCookie",
+	ezapi.SectionForm:      "ezapi.ErrMissingFormField",
+	ezapi.SectionMultipart: "ezapi.ErrMissingFormField",
+}
+
+// the conversions of the scalar kinds
+var scalarBinders = map[typeKind]string{
+	kindString:   "bind.String",
+	kindBytes:    "bind.Bytes",
+	kindBool:     "bind.Bool",
+	kindDuration: "bind.Duration",
+	kindText:     "bind.Text",
+}
+
+// the conversions of the number kinds by their bit size, 0 for int and uint
+var numberBinders = map[typeKind]map[int]string{
+	kindInt:   {0: "bind.Int", 8: "bind.Int8", 16: "bind.Int16", 32: "bind.Int32", 64: "bind.Int64"},
+	kindUint:  {0: "bind.Uint", 8: "bind.Uint8", 16: "bind.Uint16", 32: "bind.Uint32", 64: "bind.Uint64"},
+	kindFloat: {32: "bind.Float32", 64: "bind.Float64"},
+}
+
+// the conversion of the scalar type, false if it isn't one
+func scalarBinder(typ *goType) (string, bool) {
+	if byBits, ok := numberBinders[typ.kind]; ok {
+		fn, ok := byBits[typ.bits]
+		return fn, ok
+	}
+	fn, ok := scalarBinders[typ.kind]
+	return fn, ok
+}
+
+// generator of the binders of a package
+type generator struct {
+	mod *module
+	pkg *pkg
+	// imports of the generated file, besides ezapi
+	imports map[string]bool
+	// the compiled patterns, the index is the suffix of the variable name
+	patterns []string
+	errs     []error
+}
+
+// request is a struct of the package with ezapi sections
+type request struct {
+	name     string
+	pos      token.Pos
+	sections map[ezapi.Section]section
+	// hash of the fields and the tags, checked when the binder is registered
+	fingerprint string
+}
+
+// section is a tagged field of the request struct
+type section struct {
+	fieldName string
+	typ       *goType
+	pos       token.Pos
+}
+
+// a branch of an if-else chain, the branch without a condition is the final else
+type branch struct {
+	cond string
+	body string
+}
+
+func newGenerator(mod *module, p *pkg) *generator {
+	return &generator{
+		mod:     mod,
+		pkg:     p,
+		imports: map[string]bool{},
+	}
+}
+
+func (g *generator) errorf(pos token.Pos, format string, args ...any) {
+	g.errs = append(g.errs, fmt.Errorf("%s: %s", g.mod.fset.Position(pos), fmt.Sprintf(format, args...)))
+}
+
+// the request structs of the package, in the source order. Only the named
+// ones if names are given
+func (g *generator) requests(names []string) []request {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var reqs []request
+	for _, file := range g.pkg.files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if len(names) > 0 && !wanted[spec.Name.Name] {
+					continue
+				}
+				structType, ok := spec.Type.(*ast.StructType)
+				if !ok || spec.Assign.IsValid() {
+					if wanted[spec.Name.Name] {
+						g.errorf(spec.Pos(), "%s is not a struct", spec.Name.Name)
+					}
+					continue
+				}
+				req, ok := g.request(spec, structType, file)
+				if !ok {
+					if wanted[spec.Name.Name] {
+						g.errorf(spec.Pos(), "%s has no ezapi sections", spec.Name.Name)
+					}
+					continue
+				}
+				delete(wanted, spec.Name.Name)
+				reqs = append(reqs, req)
+			}
+		}
+	}
+	for _, name := range names {
+		if wanted[name] {
+			g.errs = append(g.errs, fmt.Errorf("type %s not found in %s", name, g.pkg.path))
+		}
+	}
+	return reqs
+}
+
+// collect the sections of the struct, false if it is not a request struct
+func (g *generator) request(spec *ast.TypeSpec, structType *ast.StructType, file *ast.File) (request, bool) {
+	req := request{
+		name:     spec.Name.Name,
+		pos:      spec.Pos(),
+		sections: map[ezapi.Section]section{},
+	}
+	if spec.TypeParams != nil {
+		return req, false
+	}
+	// the same hash as ezapi.binderFingerprint computes with reflection
+	h := fnv.New64a()
+	for _, field := range structType.Fields.List {
+		tag := ezapiTag(field)
+		if !slices.Contains(sectionOrder, ezapi.Section(tag)) {
+			continue
+		}
+		s := ezapi.Section(tag)
+		if _, ok := req.sections[s]; ok {
+			g.errorf(field.Pos(), "only one %s section is allowed per struct", s)
+			continue
+		}
+		typ, err := g.mod.resolve(field.Type, g.pkg, file)
+		if err != nil {
+			g.errorf(field.Pos(), "%v", err)
+			continue
+		}
+		req.sections[s] = section{fieldName: fieldName(field), typ: typ, pos: field.Pos()}
+
+		fmt.Fprintf(h, "%s %q\n", fieldName(field), string(structTag(field)))
+		if st, ok := typ.structType(); ok {
+			for _, f := range st.fields.List {
+				for _, name := range fieldNames(f) {
+					fmt.Fprintf(h, "\t%s %q\n", name, string(structTag(f)))
+				}
+			}
+		}
+	}
+	req.fingerprint = strconv.FormatUint(h.Sum64(), 16)
+	bodies := 0
+	for _, s := range []ezapi.Section{ezapi.SectionJSONBody, ezapi.SectionBody, ezapi.SectionForm, ezapi.SectionMultipart} {
+		if _, ok := req.sections[s]; ok {
+			bodies++
+		}
+	}
+	if bodies > 1 {
+		g.errorf(req.pos, "%s: only one body section (jsonBody, body, form or multipart) is allowed per struct", req.name)
+	}
+	return req, len(req.sections) > 0
+}
+
+// generate the source of the binders file, with every misconfiguration
+// of the requests reported together
+func (g *generator) generate(reqs []request) ([]byte, error) {
+	var funcs bytes.Buffer
+	for _, req := range reqs {
+		g.binder(&funcs, req)
+	}
+	if len(g.errs) > 0 {
+		return nil, errors.Join(g.errs...)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n\npackage %s\n\n", _GEN_HEADER, g.pkg.name)
+
+	var imports []string
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	buf.WriteString("import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	if len(imports) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\t\"github.com/ic-it/ezapi\"\n\t\"github.com/ic-it/ezapi/bind\"\n)\n\n")
+
+	if len(g.patterns) > 0 {
+		buf.WriteString("var (\n")
+		for i, pattern := range g.patterns {
+			fmt.Fprintf(&buf, "\t%s = regexp.MustCompile(%s)\n", patternName(i), strconv.Quote(pattern))
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	buf.WriteString("func init() {\n")
+	for _, req := range reqs {
+		fmt.Fprintf(&buf, "\tezapi.RegisterGeneratedBinder(%s, %q)\n", binderName(req.name), req.fingerprint)
+	}
+	buf.WriteString("}\n\n")
+	buf.Write(funcs.Bytes())
+	buf.WriteString(_FIELD_ERROR_FUNC)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting the generated code: %w", err)
+	}
+	return src, nil
+}
+
+func binderName(reqName string) string {
+	return "ezapiBind" + reqName
+}
+
+// write the binder function of the request
+func (g *generator) binder(w *bytes.Buffer, req request) {
+	fmt.Fprintf(w, "// %s binds %s without reflection\n", binderName(req.name), req.name)
+	fmt.Fprintf(w, "func %s(in bind.Input) (%s, error) {\n", binderName(req.name), req.name)
+	fmt.Fprintf(w, "var req %s\n", req.name)
+	w.WriteString("var fieldErrs []ezapi.FieldError\n\n")
+
+	for _, s := range sectionOrder {
+		sec, ok := req.sections[s]
+		if !ok {
+			continue
+		}
+		switch s {
+		case ezapi.SectionJSONBody, ezapi.SectionBody:
+			g.body(w, s, sec)
+		default:
+			g.params(w, s, sec)
+		}
+	}
+
+	w.WriteString("if len(fieldErrs) > 0 {\n")
+	w.WriteString("return req, ezapi.ValidationErrors{Errors: fieldErrs}\n")
+	w.WriteString("}\n")
+	w.WriteString("return req, nil\n")
+	w.WriteString("}\n\n")
+}
+
+// write the decoding of the body and the checks of its fields
+func (g *generator) body(w *bytes.Buffer, s ezapi.Section, sec section) {
+	dst := "req." + sec.fieldName
+	var checks strings.Builder
+	if st, ok := sec.typ.structType(); ok {
+		var guards []string
+		if sec.typ.kind == kindPtr {
+			guards = append(guards, dst+" != nil")
+		}
+		g.bodyChecks(&checks, dst, st, guards, 0)
+	} else if st.external {
+		g.errorf(sec.pos, "body type %s is declared outside the module, its constraints can't be generated", sec.typ.name)
+		return
+	}
+
+	fmt.Fprintf(w, "// %s\n", s)
+	var branches []branch
+	if s == ezapi.SectionBody {
+		branches = append(branches, branch{
+			cond: "in.BodyCodec == nil",
+			body: fieldErr(ezapi.SectionBody, "", "nil", "ezapi.ErrUnsupportedMediaType"),
+		})
+		branches = append(branches, branch{
+			cond: fmt.Sprintf("err := in.BodyCodec.Decode(in.Body, &%s); err != nil", dst),
+			body: fieldErr(ezapi.SectionBody, "", "nil", "err"),
+		})
+	} else {
+		branches = append(branches, branch{
+			cond: fmt.Sprintf("err := (ezapi.JSONCodec{}).Decode(in.Body, &%s); err != nil", dst),
+			body: fieldErr(ezapi.SectionBody, "", "nil", "err"),
+		})
+	}
+	if checks.Len() > 0 {
+		branches = append(branches, branch{body: checks.String()})
+	}
+	w.WriteString(chain(branches))
+	w.WriteString("\n")
+}
+
+// write the checks of the constrained fields of the body struct, the
+// promoted fields of the embedded structs included
+func (g *generator) bodyChecks(w *strings.Builder, x string, st *goType, guards []string, depth int) {
+	if depth > _MAX_TYPE_DEPTH {
+		return
+	}
+	for _, field := range st.fields.List {
+		typ, err := g.mod.resolve(field.Type, st.pkg, st.file)
+		if err != nil {
+			g.errorf(field.Pos(), "%v", err)
+			continue
+		}
+
+		if len(field.Names) == 0 {
+			// embedded struct
+			embedded, ok := typ.structType()
+			if !ok {
+				continue
+			}
+			name := fieldName(field)
+			embeddedGuards := guards
+			if typ.kind == kindPtr {
+				if !ast.IsExported(name) && st.pkg != g.pkg {
+					g.errorf(field.Pos(), "the embedded pointer %s is not accessible from %s", name, g.pkg.path)
+					continue
+				}
+				embeddedGuards = append(append([]string(nil), guards...), x+"."+name+" != nil")
+			}
+			// the promoted fields are accessed through the embedded field when possible
+			embeddedX := x
+			if ast.IsExported(name) || st.pkg == g.pkg {
+				embeddedX = x + "." + name
+			}
+			g.bodyChecks(w, embeddedX, embedded, embeddedGuards, depth+1)
+			continue
+		}
+
+		tag := ezapiTag(field)
+		if tag == "" {
+			continue
+		}
+		parsed, err := ezapi.ParseTag(ezapi.SectionBody, tag)
+		if err != nil {
+			g.errorf(field.Pos(), "%s: %s", fieldName(field), strings.ReplaceAll(err.Error(), "\n", ": "))
+			continue
+		}
+		if parsed.Constraints == nil {
+			continue
+		}
+		if err := checkConstraintTypes(typ, parsed.Constraints); err != nil {
+			g.errorf(field.Pos(), "%v", err)
+			continue
+		}
+		alias := jsonName(field)
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			alias := alias
+			if alias == "" {
+				alias = name.Name
+			}
+			dst := x + "." + name.Name
+			value, fieldGuards := derefGuards(dst, typ)
+			fieldGuards = append(append([]string(nil), guards...), fieldGuards...)
+
+			branches := g.checks(value, typ.deref(), parsed.Constraints, ezapi.SectionBody, alias, dst)
+			writeGuarded(w, fieldGuards, chain(branches))
+		}
+	}
+}
+
+// write the binding of the params of the section
+func (g *generator) params(w *bytes.Buffer, s ezapi.Section, sec section) {
+	st, ok := sec.typ.structType()
+	if !ok {
+		g.errorf(sec.pos, "%s section: expected a struct of the module, got %s", s, sec.typ.name)
+		return
+	}
+
+	base := "req." + sec.fieldName
+	fmt.Fprintf(w, "// %s\n", s)
+	if sec.typ.kind == kindPtr {
+		fmt.Fprintf(w, "bind.Alloc(&%s)\n", base)
+	}
+
+	for _, field := range st.fields.List {
+		tag := ezapiTag(field)
+		if tag == "" {
+			continue
+		}
+		parsed, err := ezapi.ParseTag(s, tag)
+		if err != nil {
+			g.errorf(field.Pos(), "%s: %s", fieldName(field), strings.ReplaceAll(err.Error(), "\n", ": "))
+			continue
+		}
+		typ, err := g.mod.resolve(field.Type, st.pkg, st.file)
+		if err != nil {
+			g.errorf(field.Pos(), "%v", err)
+			continue
+		}
+		for _, name := range fieldNames(field) {
+			if !ast.IsExported(name) && st.pkg != g.pkg {
+				g.errorf(field.Pos(), "the field %s is not accessible from %s", name, g.pkg.path)
+				continue
+			}
+			alias := parsed.Alias
+			if alias == "" {
+				alias = name
+			}
+			code, err := g.param(s, base+"."+name, typ, parsed, alias)
+			if err != nil {
+				g.errorf(field.Pos(), "%s: %v", name, err)
+				continue
+			}
+			w.WriteString(code)
+		}
+	}
+	w.WriteString("\n")
+}
+
+// the binding of a single param
+func (g *generator) param(s ezapi.Section, dst string, typ *goType, tag ezapi.TagSchema, alias string) (string, error) {
+	if err := checkConstraintTypes(typ, tag.Constraints); err != nil {
+		return "", err
+	}
+	// the raw values are exposed as is
+	if (s == ezapi.SectionMultipart && (typ.kind == kindFile || typ.kind == kindFiles)) ||
+		(s == ezapi.SectionCookie && (typ.kind == kindCookie || typ.kind == kindCookiePtr)) {
+		if tag.HasDefault {
+			return "", fmt.Errorf("%s can't have a default", typ.name)
+		}
+		if tag.Constraints != nil {
+			return "", fmt.Errorf("%s can't have constraints", typ.name)
+		}
+		var head, set string
+		switch typ.kind {
+		case kindFile:
+			head = fmt.Sprintf("files := bind.FormFiles(in.MultipartForm, %q); len(files) == 0", alias)
+			set = dst + " = files[0]"
+		case kindFiles:
+			head = fmt.Sprintf("files := bind.FormFiles(in.MultipartForm, %q); len(files) == 0", alias)
+			set = dst + " = files"
+		case kindCookie:
+			head = fmt.Sprintf("cookie := bind.FindCookie(in.Cookies, %q); cookie == nil", alias)
+			set = dst + " = *cookie"
+		case kindCookiePtr:
+			head = fmt.Sprintf("cookie := bind.FindCookie(in.Cookies, %q); cookie == nil", alias)
+			set = dst + " = cookie"
+		}
+		return chain([]branch{
+			{cond: head, body: missing(s, alias, tag, "")},
+			{body: set},
+		}), nil
+	}
+
+	_, bindable := bindExpr(typ, dst, "", "")
+	if !bindable && s != ezapi.SectionContext {
+		return "", fmt.Errorf("unsupported type %s", typ.name)
+	}
+	if tag.HasDefault {
+		if !bindable {
+			return "", fmt.Errorf("%s can't have a default", typ.name)
+		}
+		if err := checkDefault(typ, tag.Default); err != nil {
+			return "", fmt.Errorf("invalid default %q: %w", tag.Default, err)
+		}
+	}
+	defaultBind := ""
+	if tag.HasDefault {
+		defaultBind, _ = bindExpr(typ, dst, strconv.Quote(tag.Default), defaultItems(tag.Default))
+	}
+
+	if s == ezapi.SectionContext {
+		return g.contextParam(dst, typ, tag, alias, defaultBind), nil
+	}
+
+	// head of the chain, the raw value and the items of the slices
+	var head, value, items, rawValue string
+	switch s {
+	case ezapi.SectionPath:
+		head = fmt.Sprintf("value := in.PathParams[%q]; value == \"\"", alias)
+		value, items, rawValue = "value", "[]string{value}", "value"
+	case ezapi.SectionCookie:
+		head = fmt.Sprintf("cookie := bind.FindCookie(in.Cookies, %q); cookie == nil", alias)
+		value, items, rawValue = "cookie.Value", "[]string{cookie.Value}", "cookie.Value"
+	case ezapi.SectionQuery:
+		head = fmt.Sprintf("values := in.QueryParams[%q]; %s", alias, noValue(tag))
+	case ezapi.SectionHeader:
+		head = fmt.Sprintf("values := in.Header.Values(%q); %s", alias, noValue(tag))
+	case ezapi.SectionForm:
+		head = fmt.Sprintf("values := in.Form[%q]; %s", alias, noValue(tag))
+	case ezapi.SectionMultipart:
+		head = fmt.Sprintf("values := bind.FormValues(in.MultipartForm, %q); %s", alias, noValue(tag))
+	}
+	if value == "" {
+		value, items, rawValue = "values[0]", "values", "values"
+		if s == ezapi.SectionHeader {
+			// multi-value headers may be sent as several lines or as a comma separated list
+			items = "bind.SplitHeaderValues(values)"
+		}
+	}
+
+	bind, _ := bindExpr(typ, dst, value, items)
+	branches := []branch{
+		{cond: head, body: missing(s, alias, tag, defaultBind)},
+		{cond: fmt.Sprintf("err := %s; err != nil", bind), body: fieldErr(s, alias, rawValue, "err")},
+	}
+	// the pointers are allocated by the binding
+	checked, _ := derefGuards(dst, typ)
+	branches = append(branches, g.checks(checked, typ.deref(), tag.Constraints, s, alias, dst)...)
+	return chain(branches), nil
+}
+
+// the binding of a context value: stored with the field type, or as a
+// string converted to it
+func (g *generator) contextParam(dst string, typ *goType, tag ezapi.TagSchema, alias, defaultBind string) string {
+	s := ezapi.SectionContext
+	branches := []branch{
+		// the context returns nil for the missing keys
+		{cond: fmt.Sprintf("value := in.ContextValues[%q]; value == nil", alias), body: missing(s, alias, tag, defaultBind)},
+	}
+
+	checked, guards := derefGuards(dst, typ)
+	if bind, ok := bindExpr(typ, dst, "s", "[]string{s}"); ok && typ.name != "string" {
+		converted := []branch{
+			{cond: fmt.Sprintf("err := %s; err != nil", bind), body: fieldErr(s, alias, "s", "err")},
+		}
+		converted = append(converted, g.checks(checked, typ.deref(), tag.Constraints, s, alias, dst)...)
+		branches = append(branches, branch{
+			cond: fmt.Sprintf("s, ok := value.(string); ok && !bind.Value(&%s, value)", dst),
+			body: chain(converted),
+		})
+	}
+	branches = append(branches, branch{
+		cond: fmt.Sprintf("!bind.Value(&%s, value)", dst),
+		body: fieldErr(s, alias, "nil", "ezapi.ErrTypeMismatch"),
+	})
+
+	checks := g.checks(checked, typ.deref(), tag.Constraints, s, alias, dst)
+	if len(checks) > 0 {
+		if len(guards) > 0 {
+			var b strings.Builder
+			writeGuarded(&b, guards, chain(checks))
+			branches = append(branches, branch{body: b.String()})
+		} else {
+			branches = append(branches, checks...)
+		}
+	}
+	return chain(branches)
+}
+
+// the condition of the missing values, the empty value of the optional and
+// defaulted params is treated as missing
+func noValue(tag ezapi.TagSchema) string {
+	if tag.Optional || tag.HasDefault {
+		return "bind.NoValue(values)"
+	}
+	return "len(values) == 0"
+}
+
+// the body of the branch of the missing param
+func missing(s ezapi.Section, alias string, tag ezapi.TagSchema, defaultBind string) string {
+	switch {
+	case defaultBind != "":
+		return "_ = " + defaultBind + " // the default is checked when the handler is registered"
+	case tag.Optional:
+		return "// optional"
+	}
+	return fieldErr(s, alias, "nil", missingErrs[s])
+}
+
+// the binding expression of the field from the raw value, or from the
+// items for the slices. False if the type can't be bound from strings
+func bindExpr(typ *goType, dst, value, items string) (string, bool) {
+	switch typ.kind {
+	case kindPtr:
+		if fn, ok := scalarBinder(typ.elem); ok {
+			return fmt.Sprintf("%s(bind.Alloc(&%s), %s)", fn, dst, value), true
+		}
+	case kindSlice:
+		if fn, ok := scalarBinder(typ.elem); ok {
+			return fmt.Sprintf("bind.Slice(&%s, %s, %s)", dst, items, fn), true
+		}
+	default:
+		if fn, ok := scalarBinder(typ); ok {
+			return fmt.Sprintf("%s(&%s, %s)", fn, dst, value), true
+		}
+	}
+	return "", false
+}
+
+// the checks of the constraints of the value, as branches of an if-else
+// chain. The pointers are dereferenced by the caller
+func (g *generator) checks(x string, typ *goType, c *ezapi.Constraints, s ezapi.Section, alias, fieldValue string) []branch {
+	if c == nil {
+		return nil
+	}
+	check := func(expr string) branch {
+		return branch{
+			cond: fmt.Sprintf("err := %s; err != nil", expr),
+			body: fieldErr(s, alias, fieldValue, "err"),
+		}
+	}
+
+	var branches []branch
+	if typ.kind == kindSlice {
+		if c.MinLen != nil {
+			branches = append(branches, check(fmt.Sprintf("bind.CheckMinLen(len(%s), %d)", x, *c.MinLen)))
+		}
+		if c.MaxLen != nil {
+			branches = append(branches, check(fmt.Sprintf("bind.CheckMaxLen(len(%s), %d)", x, *c.MaxLen)))
+		}
+
+		item, guards := derefGuards("item", typ.elem)
+		var itemChecks []string
+		for _, expr := range g.valueChecks(item, typ.elem.deref(), c) {
+			itemChecks = append(itemChecks, fmt.Sprintf("if err := %s; err != nil {\n%s\nbreak\n}\n",
+				expr, fieldErr(s, alias, fieldValue, "bind.ItemError(i, err)")))
+		}
+		if len(itemChecks) > 0 {
+			var loop strings.Builder
+			fmt.Fprintf(&loop, "for i, item := range %s {\n", x)
+			if len(guards) > 0 {
+				fmt.Fprintf(&loop, "if !(%s) {\ncontinue\n}\n", strings.Join(guards, " && "))
+			}
+			loop.WriteString(strings.Join(itemChecks, ""))
+			loop.WriteString("}")
+			branches = append(branches, branch{body: loop.String()})
+		}
+		return branches
+	}
+
+	if typ.kind == kindString {
+		if c.MinLen != nil || c.MaxLen != nil {
+			g.imports["unicode/utf8"] = true
+		}
+		if c.MinLen != nil {
+			branches = append(branches, check(fmt.Sprintf("bind.CheckMinLen(utf8.RuneCountInString(string(%s)), %d)", x, *c.MinLen)))
+		}
+		if c.MaxLen != nil {
+			branches = append(branches, check(fmt.Sprintf("bind.CheckMaxLen(utf8.RuneCountInString(string(%s)), %d)", x, *c.MaxLen)))
+		}
+	}
+	for _, expr := range g.valueChecks(x, typ, c) {
+		branches = append(branches, check(expr))
+	}
+	return branches
+}
+
+// the checks of a single value, the length is checked by the caller
+func (g *generator) valueChecks(x string, typ *goType, c *ezapi.Constraints) []string {
+	var exprs []string
+	if c.Min != nil {
+		exprs = append(exprs, fmt.Sprintf("bind.CheckMin(float64(%s), %s)", x, strconv.FormatFloat(*c.Min, 'g', -1, 64)))
+	}
+	if c.Max != nil {
+		exprs = append(exprs, fmt.Sprintf("bind.CheckMax(float64(%s), %s)", x, strconv.FormatFloat(*c.Max, 'g', -1, 64)))
+	}
+	if len(c.Enum) > 0 {
+		g.imports["fmt"] = true
+		values := make([]string, len(c.Enum))
+		for i, value := range c.Enum {
+			values[i] = strconv.Quote(value)
+		}
+		exprs = append(exprs, fmt.Sprintf("bind.CheckEnum(fmt.Sprint(%s), %s)", x, strings.Join(values, ", ")))
+	}
+	if typ.kind != kindString {
+		return exprs
+	}
+	if c.Pattern != "" {
+		exprs = append(exprs, fmt.Sprintf("bind.CheckPattern(%s, string(%s))", g.pattern(c.Pattern), x))
+	}
+	if c.Format != "" {
+		exprs = append(exprs, fmt.Sprintf("bind.CheckFormat(%q, string(%s))", c.Format, x))
+	}
+	return exprs
+}
+
+// the variable of the compiled pattern, shared by the fields with the same pattern
+func (g *generator) pattern(pattern string) string {
+	for i, p := range g.patterns {
+		if p == pattern {
+			return patternName(i)
+		}
+	}
+	g.imports["regexp"] = true
+	g.patterns = append(g.patterns, pattern)
+	return patternName(len(g.patterns) - 1)
+}
+
+func patternName(i int) string {
+	return fmt.Sprintf("ezapiPattern%d", i)
+}
+
+// check the constraints kinds against the field type, like ReflectReq does
+func checkConstraintTypes(typ *goType, c *ezapi.Constraints) error {
+	if c == nil {
+		return nil
+	}
+	typ = typ.deref()
+	isList := typ.kind == kindSlice
+	elem := typ
+	if isList {
+		elem = typ.elem.deref()
+	}
+	if (c.Min != nil || c.Max != nil) && !elem.isNumber() {
+		return fmt.Errorf("min/max constraints need a number, got %s", elem.name)
+	}
+	if (c.MinLen != nil || c.MaxLen != nil) && !isList && elem.kind != kindString {
+		return fmt.Errorf("minLen/maxLen constraints need a string or a slice, got %s", typ.name)
+	}
+	if (c.Pattern != "" || c.Format != "") && elem.kind != kindString {
+		return fmt.Errorf("pattern/format constraints need a string, got %s", elem.name)
+	}
+	return nil
+}
+
+// check the default of the basic kinds, the others are checked by ReflectReq
+func checkDefault(typ *goType, value string) error {
+	items := []string{value}
+	if typ.kind == kindSlice {
+		items = strings.Split(value, "|")
+	}
+	elem := typ.deref()
+	if typ.kind == kindSlice {
+		elem = typ.elem
+	}
+	for _, item := range items {
+		var err error
+		switch elem.kind {
+		case kindInt:
+			_, err = strconv.ParseInt(item, 10, 64)
+		case kindUint:
+			_, err = strconv.ParseUint(item, 10, 64)
+		case kindFloat:
+			_, err = strconv.ParseFloat(item, 64)
+		case kindBool:
+			_, err = strconv.ParseBool(item)
+		case kindDuration:
+			_, err = time.ParseDuration(item)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// the items of a slice default, separated by '|'
+func defaultItems(value string) string {
+	items := strings.Split(value, "|")
+	for i, item := range items {
+		items[i] = strconv.Quote(item)
+	}
+	return "[]string{" + strings.Join(items, ", ") + "}"
+}
+
+// the dereferenced value of the field and the nil checks it needs
+func derefGuards(x string, typ *goType) (string, []string) {
+	var guards []string
+	for ; typ.kind == kindPtr; typ = typ.elem {
+		guards = append(guards, x+" != nil")
+		x = "*" + x
+	}
+	return x, guards
+}
+
+// write the code, run only if the guards hold
+func writeGuarded(w *strings.Builder, guards []string, code string) {
+	if code == "" {
+		return
+	}
+	if len(guards) == 0 {
+		w.WriteString(code)
+		return
+	}
+	fmt.Fprintf(w, "if %s {\n%s}\n", strings.Join(guards, " && "), code)
+}
+
+// render the branches as an if-else chain
+func chain(branches []branch) string {
+	var b strings.Builder
+	for i, br := range branches {
+		if i == 0 {
+			fmt.Fprintf(&b, "if %s {\n", br.cond)
+		} else if br.cond == "" {
+			fmt.Fprintf(&b, "} else {\n%s\n", strings.TrimRight(br.body, "\n"))
+			break
+		} else {
+			fmt.Fprintf(&b, "} else if %s {\n", br.cond)
+		}
+		b.WriteString(strings.TrimRight(br.body, "\n"))
+		b.WriteString("\n")
+	}
+	if len(branches) > 0 {
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// the statement appending the failure of the param
+func fieldErr(s ezapi.Section, alias, value, err string) string {
+	if s == ezapi.SectionJSONBody {
+		s = ezapi.SectionBody
+	}
+	location := string(s)
+	if alias != "" {
+		location += "." + alias
+	}
+	return fmt.Sprintf("fieldErrs = append(fieldErrs, ezapiFieldError(%q, %s, %s))", location, value, err)
+}
+
+// the constructor of the failures, generated into every binders file
+const _FIELD_ERROR_FUNC = `
+// the binding failure of the field at the location
+func ezapiFieldError(location string, value any, err error) ezapi.FieldError {
+	return ezapi.FieldError{Location: location, Reason: err.Error(), Value: value, Err: err}
+}
+`
+
+// the value of the ezapi key of the field tag
+func ezapiTag(field *ast.Field) string {
+	return structTag(field).Get("ezapi")
+}
+
+// the name of the field in the json documents, empty if it has none
+func jsonName(field *ast.Field) string {
+	name, _, _ := strings.Cut(structTag(field).Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func structTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag)
+}
+
+// the names declared by the field, the type name for the embedded fields
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		return []string{fieldName(field)}
+	}
+	names := make([]string, len(field.Names))
+	for i, name := range field.Names {
+		names[i] = name.Name
+	}
+	return names
+}
+
+// the name of the field, the type name for the embedded fields
+func fieldName(field *ast.Field) string {
+	if len(field.Names) > 0 {
+		return field.Names[0].Name
+	}
+	expr := field.Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return expr.Sel.Name
+	}
+	return ""
+}
@@ -0,0 +1,541 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// the header of the generated files, they are skipped when loading
+const _GEN_HEADER = "// Code generated by ezapi gen. DO NOT EDIT."
+
+// the module of the generated package, its packages are loaded on demand
+// to resolve the field types
+type module struct {
+	path string
+	dir  string
+	fset *token.FileSet
+	pkgs map[string]*pkg
+	// type-checks the packages outside the module, created on demand
+	importer types.ImporterFrom
+}
+
+// pkg is a parsed package of the module
+type pkg struct {
+	name  string
+	path  string
+	dir   string
+	files []*ast.File
+	// type declarations by name
+	types map[string]typeDecl
+	// method names by receiver type name
+	methods map[string]map[string]bool
+}
+
+type typeDecl struct {
+	spec *ast.TypeSpec
+	file *ast.File
+}
+
+// find the module of the directory by walking up to the go.mod
+func loadModule(dir string) (*module, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for d := dir; ; d = filepath.Dir(d) {
+		modPath, err := readModulePath(filepath.Join(d, "go.mod"))
+		if err == nil {
+			return &module{
+				path: modPath,
+				dir:  d,
+				fset: token.NewFileSet(),
+				pkgs: map[string]*pkg{},
+			}, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if filepath.Dir(d) == d {
+			return nil, fmt.Errorf("no go.mod found for %s", dir)
+		}
+	}
+}
+
+// helper function to read the module directive of the go.mod
+func readModulePath(goMod string) (string, error) {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			modPath := strings.TrimSpace(rest)
+			if unquoted, err := strconv.Unquote(modPath); err == nil {
+				modPath = unquoted
+			}
+			return modPath, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s: no module directive", goMod)
+}
+
+// import path of the directory of the module
+func (m *module) importPath(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(m.dir, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the module %s", dir, m.path)
+	}
+	if rel == "." {
+		return m.path, nil
+	}
+	return path.Join(m.path, filepath.ToSlash(rel)), nil
+}
+
+// directory of the import path, false if the package is outside the module
+func (m *module) dirOf(importPath string) (string, bool) {
+	if importPath == m.path {
+		return m.dir, true
+	}
+	rest, ok := strings.CutPrefix(importPath, m.path+"/")
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(m.dir, filepath.FromSlash(rest)), true
+}
+
+// load the package of the module, the test files and the generated files are skipped
+func (m *module) load(importPath string) (*pkg, error) {
+	if p, ok := m.pkgs[importPath]; ok {
+		return p, nil
+	}
+	dir, ok := m.dirOf(importPath)
+	if !ok {
+		return nil, fmt.Errorf("package %s is outside the module %s", importPath, m.path)
+	}
+
+	buildPkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	p := &pkg{
+		name:    buildPkg.Name,
+		path:    importPath,
+		dir:     dir,
+		types:   map[string]typeDecl{},
+		methods: map[string]map[string]bool{},
+	}
+	for _, name := range buildPkg.GoFiles {
+		file, err := parser.ParseFile(m.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if isGenerated(file) {
+			continue
+		}
+		p.files = append(p.files, file)
+		p.collect(file)
+	}
+	m.pkgs[importPath] = p
+	return p, nil
+}
+
+func isGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if comment.Text == _GEN_HEADER {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collect the type declarations and the method names of the file
+func (p *pkg) collect(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				p.types[spec.Name.Name] = typeDecl{spec: spec, file: file}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				continue
+			}
+			recv := decl.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			ident, ok := recv.(*ast.Ident)
+			if !ok {
+				// methods of generic types are not needed
+				continue
+			}
+			if p.methods[ident.Name] == nil {
+				p.methods[ident.Name] = map[string]bool{}
+			}
+			p.methods[ident.Name][decl.Name.Name] = true
+		}
+	}
+}
+
+// The kinds of the field types, as the binders see them
+type typeKind int
+
+const (
+	kindUnsupported typeKind = iota
+	kindString
+	kindBytes
+	kindInt
+	kindUint
+	kindFloat
+	kindBool
+	kindDuration
+	// types implementing encoding.TextUnmarshaler, e.g. uuid.UUID, time.Time
+	kindText
+	kindStruct
+	kindPtr
+	kindSlice
+	kindCookie
+	kindCookiePtr
+	kindFile
+	kindFiles
+	// maps, interfaces, funcs, etc. only context values can have them
+	kindOther
+)
+
+// goType is a resolved field type
+type goType struct {
+	kind typeKind
+	// bit size of the numbers, 0 for int and uint
+	bits int
+	// source of the type, for the error messages
+	name string
+	elem *goType
+	// fields of the struct types, resolved in the package and the file declaring them
+	fields *ast.FieldList
+	pkg    *pkg
+	file   *ast.File
+	// the type is declared outside the module, its fields are unknown
+	external bool
+}
+
+// the scalar kinds are bound from a single string
+func (t *goType) isScalar() bool {
+	switch t.kind {
+	case kindString, kindBytes, kindInt, kindUint, kindFloat, kindBool, kindDuration, kindText:
+		return true
+	}
+	return false
+}
+
+// the number kinds accept the min/max constraints
+func (t *goType) isNumber() bool {
+	return t.kind == kindInt || t.kind == kindUint || t.kind == kindFloat
+}
+
+// the type with the pointers removed
+func (t *goType) deref() *goType {
+	for t.kind == kindPtr {
+		t = t.elem
+	}
+	return t
+}
+
+// the struct type of a section or a body, with the pointer removed
+func (t *goType) structType() (*goType, bool) {
+	if t.kind == kindPtr {
+		t = t.elem
+	}
+	return t, t.kind == kindStruct && !t.external
+}
+
+var builtinKinds = map[string]typeKind{
+	"string":  kindString,
+	"int":     kindInt,
+	"int8":    kindInt,
+	"int16":   kindInt,
+	"int32":   kindInt,
+	"rune":    kindInt,
+	"int64":   kindInt,
+	"uint":    kindUint,
+	"uint8":   kindUint,
+	"byte":    kindUint,
+	"uint16":  kindUint,
+	"uint32":  kindUint,
+	"uint64":  kindUint,
+	"float32": kindFloat,
+	"float64": kindFloat,
+	"bool":    kindBool,
+	"any":     kindOther,
+}
+
+// bit sizes of the builtin numbers, int and uint have none
+var builtinBits = map[string]int{
+	"int8":    8,
+	"int16":   16,
+	"int32":   32,
+	"rune":    32,
+	"int64":   64,
+	"uint8":   8,
+	"byte":    8,
+	"uint16":  16,
+	"uint32":  32,
+	"uint64":  64,
+	"float32": 32,
+	"float64": 64,
+}
+
+// the max depth of the named types resolved through each other
+const _MAX_TYPE_DEPTH = 32
+
+// resolve the type expression of the file of the package
+func (m *module) resolve(expr ast.Expr, p *pkg, file *ast.File) (*goType, error) {
+	return m.resolveDepth(expr, p, file, 0)
+}
+
+func (m *module) resolveDepth(expr ast.Expr, p *pkg, file *ast.File, depth int) (*goType, error) {
+	if depth > _MAX_TYPE_DEPTH {
+		return nil, fmt.Errorf("type %s is too deeply nested", exprString(expr))
+	}
+	name := exprString(expr)
+
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return m.resolveDepth(expr.X, p, file, depth+1)
+
+	case *ast.Ident:
+		if decl, ok := p.types[expr.Name]; ok {
+			return m.resolveNamed(p, decl, name, depth)
+		}
+		if kind, ok := builtinKinds[expr.Name]; ok {
+			return &goType{kind: kind, bits: builtinBits[expr.Name], name: name}, nil
+		}
+		return &goType{kind: kindUnsupported, name: name}, nil
+
+	case *ast.SelectorExpr:
+		x, ok := expr.X.(*ast.Ident)
+		if !ok {
+			return &goType{kind: kindUnsupported, name: name}, nil
+		}
+		importPath, err := m.importOf(file, x.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := m.dirOf(importPath); ok {
+			imported, err := m.load(importPath)
+			if err != nil {
+				return nil, err
+			}
+			decl, ok := imported.types[expr.Sel.Name]
+			if !ok {
+				return nil, fmt.Errorf("type %s not found in %s", expr.Sel.Name, importPath)
+			}
+			return m.resolveNamed(imported, decl, name, depth)
+		}
+		return m.externalType(importPath, expr.Sel.Name, name)
+
+	case *ast.StarExpr:
+		elem, err := m.resolveDepth(expr.X, p, file, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		switch elem.kind {
+		case kindCookie:
+			return &goType{kind: kindCookiePtr, name: name}, nil
+		case kindFile:
+			// multipart.FileHeader is only bound through a pointer
+			return &goType{kind: kindFile, name: name}, nil
+		}
+		return &goType{kind: kindPtr, name: name, elem: elem}, nil
+
+	case *ast.ArrayType:
+		if expr.Len != nil {
+			return &goType{kind: kindUnsupported, name: name}, nil
+		}
+		elem, err := m.resolveDepth(expr.Elt, p, file, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if elem.kind == kindUint && (elem.name == "byte" || elem.name == "uint8") {
+			return &goType{kind: kindBytes, name: name}, nil
+		}
+		if elem.kind == kindFile {
+			return &goType{kind: kindFiles, name: name}, nil
+		}
+		return &goType{kind: kindSlice, name: name, elem: elem}, nil
+
+	case *ast.StructType:
+		return &goType{kind: kindStruct, name: name, fields: expr.Fields, pkg: p, file: file}, nil
+
+	case *ast.MapType, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return &goType{kind: kindOther, name: name}, nil
+	}
+	return &goType{kind: kindUnsupported, name: name}, nil
+}
+
+// resolve the declared type, the named types keep their own UnmarshalText
+func (m *module) resolveNamed(p *pkg, decl typeDecl, name string, depth int) (*goType, error) {
+	if decl.spec.TypeParams != nil {
+		return &goType{kind: kindUnsupported, name: name}, nil
+	}
+	if p.methods[decl.spec.Name.Name]["UnmarshalText"] {
+		return &goType{kind: kindText, name: name}, nil
+	}
+
+	underlying, err := m.resolveDepth(decl.spec.Type, p, decl.file, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	t := *underlying
+	t.name = name
+	if decl.spec.Assign.IsValid() {
+		// aliases are the same type
+		return &t, nil
+	}
+	switch t.kind {
+	case kindDuration:
+		// the methods are not inherited, the new type is a plain int64
+		t.kind = kindInt
+		t.bits = 64
+	case kindText, kindCookie, kindCookiePtr, kindFile, kindFiles:
+		t.kind = kindUnsupported
+	}
+	return &t, nil
+}
+
+// the types of the packages outside the module, their method sets and
+// underlying types are checked with go/types
+func (m *module) externalType(importPath, typeName, name string) (*goType, error) {
+	switch importPath + "." + typeName {
+	case "time.Duration":
+		return &goType{kind: kindDuration, name: name}, nil
+	case "net/http.Cookie":
+		return &goType{kind: kindCookie, name: name}, nil
+	case "mime/multipart.FileHeader":
+		return &goType{kind: kindFile, name: name}, nil
+	}
+
+	if m.importer == nil {
+		m.importer = importer.ForCompiler(m.fset, "source", nil).(types.ImporterFrom)
+	}
+	imported, err := m.importer.ImportFrom(importPath, m.dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", importPath, err)
+	}
+	obj, ok := imported.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typeName, importPath)
+	}
+	if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return &goType{kind: kindUnsupported, name: name, external: true}, nil
+	}
+	if isTextUnmarshaler(obj.Type()) {
+		// e.g. time.Time and uuid.UUID
+		return &goType{kind: kindText, name: name, external: true}, nil
+	}
+
+	switch underlying := obj.Type().Underlying().(type) {
+	case *types.Basic:
+		if kind, ok := builtinKinds[underlying.Name()]; ok && kind != kindOther {
+			return &goType{kind: kind, bits: builtinBits[underlying.Name()], name: name, external: true}, nil
+		}
+	case *types.Slice:
+		if elem, ok := underlying.Elem().(*types.Basic); ok && elem.Kind() == types.Byte {
+			return &goType{kind: kindBytes, name: name, external: true}, nil
+		}
+	case *types.Struct:
+		// the fields of the structs outside the module are not bound
+		return &goType{kind: kindStruct, name: name, external: true}, nil
+	case *types.Map, *types.Interface, *types.Signature, *types.Chan:
+		return &goType{kind: kindOther, name: name, external: true}, nil
+	}
+	return &goType{kind: kindUnsupported, name: name, external: true}, nil
+}
+
+// reports whether the pointer to the type implements encoding.TextUnmarshaler
+func isTextUnmarshaler(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, nil, "UnmarshalText")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 1 && sig.Results().Len() == 1 &&
+		types.Identical(sig.Params().At(0).Type(), types.NewSlice(types.Typ[types.Byte])) &&
+		types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
+
+// import path of the package name in the file
+func (m *module) importOf(file *ast.File, name string) (string, error) {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return "", err
+		}
+		if spec.Name != nil {
+			if spec.Name.Name == name {
+				return importPath, nil
+			}
+			continue
+		}
+		if m.packageName(importPath) == name {
+			return importPath, nil
+		}
+	}
+	return "", fmt.Errorf("%s: no import of package %s", m.fset.Position(file.Package).Filename, name)
+}
+
+// name of the imported package: parsed for the module packages, guessed
+// from the import path for the others
+func (m *module) packageName(importPath string) string {
+	if _, ok := m.dirOf(importPath); ok {
+		if p, err := m.load(importPath); err == nil {
+			return p.name
+		}
+	}
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		// major version suffix, e.g. example.com/mod/v2
+		name = path.Base(path.Dir(importPath))
+	}
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexAny(name, ".-"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func exprString(expr ast.Expr) string {
+	return types.ExprString(expr)
+}
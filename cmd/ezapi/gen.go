@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// the default name of the generated file, in the package directory
+const _DEFAULT_OUTPUT = "ezapi-gen.go"
+
+// gen command: write the binders of the request structs of the package
+func runGen(args []string) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	typeNames := flags.String("type", "", "comma separated request structs, by default all the structs with ezapi sections")
+	output := flags.String("output", _DEFAULT_OUTPUT, "generated file, relative to the package directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("gen takes a single package directory, got %d", flags.NArg())
+	}
+	dir := "."
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+
+	mod, err := loadModule(dir)
+	if err != nil {
+		return err
+	}
+	importPath, err := mod.importPath(dir)
+	if err != nil {
+		return err
+	}
+	p, err := mod.load(importPath)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	if *typeNames != "" {
+		for _, name := range strings.Split(*typeNames, ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+
+	g := newGenerator(mod, p)
+	reqs := g.requests(names)
+	if len(reqs) == 0 && len(g.errs) == 0 {
+		return fmt.Errorf("no request structs with ezapi sections in %s", importPath)
+	}
+	src, err := g.generate(reqs)
+	if err != nil {
+		return err
+	}
+
+	out := *output
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(p.dir, out)
+	}
+	return os.WriteFile(out, src, 0o644)
+}
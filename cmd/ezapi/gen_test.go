@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// generate the binders of the package in the directory
+func generateDir(t *testing.T, dir string) ([]byte, error) {
+	t.Helper()
+	mod, err := loadModule(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	importPath, err := mod.importPath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := mod.load(importPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := newGenerator(mod, p)
+	return g.generate(g.requests(nil))
+}
+
+func TestGenGolden(t *testing.T) {
+	dir := filepath.Join("testdata", "gen")
+	src, err := generateDir(t, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join(dir, "ezapi-gen.go.golden")
+	if *update {
+		if err := os.WriteFile(golden, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Errorf("the generated binders differ from %s, run go test -update if the change is expected:\n%s", golden, src)
+	}
+	// an unused import doesn't compile
+	if bytes.Contains(src, []byte(`"unicode/utf8"`)) {
+		t.Error("unicode/utf8 is imported without length constraints")
+	}
+}
+
+func TestGenExternalTypes(t *testing.T) {
+	_, err := generateDir(t, filepath.Join("testdata", "external"))
+	if err == nil {
+		t.Fatal("expected url.URL to be reported")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "Site: unsupported type url.URL") {
+		t.Errorf("expected the unsupported field to be reported, got %q", msg)
+	}
+	if strings.Contains(msg, "Addr") || strings.Contains(msg, "Month") {
+		t.Errorf("expected netip.Addr and time.Month to be supported, got %q", msg)
+	}
+}
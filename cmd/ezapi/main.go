@@ -0,0 +1,45 @@
+// Command ezapi is the code generator of the ezapi package.
+//
+// Usage:
+//
+//	ezapi gen [-type A,B] [-output ezapi-gen.go] [dir]
+//
+// gen reads the request structs with `ezapi` tags of the package in dir and
+// writes their reflection-free binders, which ezapi.H picks up automatically.
+// It is meant to be run by go:generate:
+//
+//	//go:generate go run github.com/ic-it/ezapi/cmd/ezapi gen
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "gen":
+		err = runGen(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "ezapi: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ezapi:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ezapi gen [-type A,B] [-output file] [dir]")
+}
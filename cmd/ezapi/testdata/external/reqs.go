@@ -0,0 +1,17 @@
+package external
+
+import (
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+// netip.Addr implements encoding.TextUnmarshaler, time.Month is an int
+// and url.URL is neither
+type ExternalReq struct {
+	Query struct {
+		Addr  netip.Addr `ezapi:"addr"`
+		Month time.Month `ezapi:"month"`
+		Site  url.URL    `ezapi:"site"`
+	} `ezapi:"query"`
+}
@@ -0,0 +1,27 @@
+package gen
+
+// no length constraints, the binders don't need unicode/utf8
+type LookupReq struct {
+	Path struct {
+		Code string `ezapi:"code,pattern=^\\d{1,3}$"`
+	} `ezapi:"path"`
+	Query struct {
+		Limit  int      `ezapi:"limit,default=20,max=100"`
+		Cursor *string  `ezapi:"cursor,optional,format=uuid"`
+		Pages  []uint16 `ezapi:"page,optional"`
+		Ratio  *float32 `ezapi:"ratio,optional"`
+	} `ezapi:"query"`
+	Header struct {
+		RequestID string `ezapi:"X-Request-Id,optional"`
+	} `ezapi:"header"`
+}
+
+type Item struct {
+	Name  string   `json:"name" ezapi:"enum=a|b"`
+	Count int      `json:"count" ezapi:"min=1"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type CreateReq struct {
+	JSONBody *Item `ezapi:"jsonBody"`
+}
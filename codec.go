@@ -0,0 +1,230 @@
+package ezapi
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Codec decodes request bodies and encodes responses of the given media types.
+// Implement it to support formats like MessagePack, CBOR or YAML
+type Codec interface {
+	// Media types handled by the codec, the first one is used for the responses
+	MediaTypes() []string
+	Decode(r io.Reader, v any) error
+	Encode(w io.Writer, v any) error
+}
+
+// JSON codec based on encoding/json
+type JSONCodec struct{}
+
+func (JSONCodec) MediaTypes() []string {
+	return []string{"application/json"}
+}
+
+func (JSONCodec) Decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+func (JSONCodec) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// Suffix makes the codec handle the +json media types, e.g. application/problem+json
+func (JSONCodec) Suffix() string {
+	return "json"
+}
+
+// XML codec based on encoding/xml. It isn't registered by default,
+// add it with RegisterCodec or Codecs
+type XMLCodec struct{}
+
+func (XMLCodec) MediaTypes() []string {
+	return []string{"application/xml", "text/xml"}
+}
+
+func (XMLCodec) Decode(r io.Reader, v any) error {
+	return xml.NewDecoder(r).Decode(v)
+}
+
+func (XMLCodec) Encode(w io.Writer, v any) error {
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// encoding/xml can't handle the maps
+func (XMLCodec) Supports(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() != reflect.Map
+}
+
+var (
+	globalCodecsMu sync.RWMutex
+	globalCodecs   = []Codec{JSONCodec{}, CSVCodec{}}
+)
+
+// RegisterCodec adds the codec to every handler built after the call.
+// Codecs registered later take precedence for the same media type
+func RegisterCodec(codec Codec) {
+	globalCodecsMu.Lock()
+	defer globalCodecsMu.Unlock()
+	globalCodecs = append([]Codec{codec}, globalCodecs...)
+}
+
+// Codecs adds codecs to the handler, they take precedence over the global ones
+func Codecs(codecs ...Codec) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.codecs = append(o.codecs, codecs...)
+	}
+}
+
+// handler codecs followed by the global ones
+func (o *handlerOpts) allCodecs() []Codec {
+	globalCodecsMu.RLock()
+	defer globalCodecsMu.RUnlock()
+	codecs := make([]Codec, 0, len(o.codecs)+len(globalCodecs))
+	codecs = append(codecs, o.codecs...)
+	codecs = append(codecs, globalCodecs...)
+	return codecs
+}
+
+// find the codec for the request Content-Type. An empty content type
+// is decoded with the first codec
+func codecForContentType(codecs []Codec, contentType string) Codec {
+	if len(codecs) == 0 {
+		return nil
+	}
+	if contentType == "" {
+		return codecs[0]
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	return codecForMediaType(codecs, mediaType)
+}
+
+// SuffixCodec is implemented by the codecs handling the media types with
+// their structured syntax suffix, e.g. application/problem+json for "json"
+type SuffixCodec interface {
+	Codec
+	Suffix() string
+}
+
+// find the codec for the media type
+func codecForMediaType(codecs []Codec, mediaType string) Codec {
+	_, codec := matchMediaType(codecs, mediaType)
+	return codec
+}
+
+// find the codec for the media type and its own media type matching it.
+// Structured syntax suffixes (e.g. application/problem+json) only match
+// the codecs opting in with SuffixCodec
+func matchMediaType(codecs []Codec, mediaType string) (string, Codec) {
+	mediaType = strings.ToLower(mediaType)
+	for _, codec := range codecs {
+		for _, mt := range codec.MediaTypes() {
+			if mt == mediaType {
+				return mt, codec
+			}
+		}
+	}
+	if i := strings.LastIndex(mediaType, "+"); i >= 0 {
+		suffix := mediaType[i+1:]
+		for _, codec := range codecs {
+			if sc, ok := codec.(SuffixCodec); ok && sc.Suffix() == suffix {
+				return codec.MediaTypes()[0], codec
+			}
+		}
+	}
+	return "", nil
+}
+
+// implemented by codecs that can handle only some of the types
+type TypedCodec interface {
+	Codec
+	Supports(t reflect.Type) bool
+}
+
+// codecs able to handle the given type
+func codecsForType(codecs []Codec, t reflect.Type) []Codec {
+	supported := make([]Codec, 0, len(codecs))
+	for _, codec := range codecs {
+		if typed, ok := codec.(TypedCodec); ok && !typed.Supports(t) {
+			continue
+		}
+		supported = append(supported, codec)
+	}
+	return supported
+}
+
+// accepted media range with its quality
+type acceptedMediaRange struct {
+	mediaRange string
+	quality    float64
+}
+
+// parse the Accept header, the most preferred media ranges first
+func parseAccept(accept string) []acceptedMediaRange {
+	var ranges []acceptedMediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptedMediaRange{mediaRange: mediaRange, quality: quality})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+	return ranges
+}
+
+// select the response media type and codec for the Accept header.
+// The default media type is used when the client accepts anything
+func negotiateCodec(codecs []Codec, defaultMediaType string, defaultCodec Codec, accept string) (string, Codec) {
+	if strings.TrimSpace(accept) == "" {
+		return defaultMediaType, defaultCodec
+	}
+	for _, ar := range parseAccept(accept) {
+		if ar.quality <= 0 {
+			continue
+		}
+		typ, subtype, _ := strings.Cut(ar.mediaRange, "/")
+		switch {
+		case ar.mediaRange == "*/*":
+			return defaultMediaType, defaultCodec
+		case subtype == "*":
+			if strings.HasPrefix(defaultMediaType, typ+"/") {
+				return defaultMediaType, defaultCodec
+			}
+			for _, codec := range codecs {
+				for _, mt := range codec.MediaTypes() {
+					if strings.HasPrefix(mt, typ+"/") {
+						return mt, codec
+					}
+				}
+			}
+		case ar.mediaRange == defaultMediaType:
+			return defaultMediaType, defaultCodec
+		default:
+			if mt, codec := matchMediaType(codecs, ar.mediaRange); codec != nil {
+				return mt, codec
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,88 @@
+package ezapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+func TestNegotiateCodec(t *testing.T) {
+	withXML := []Codec{JSONCodec{}, XMLCodec{}}
+	structType := reflect.TypeOf(struct{ Name string }{})
+	mapType := reflect.TypeOf(map[string]int{})
+
+	for _, tc := range []struct {
+		name      string
+		codecs    []Codec
+		accept    string
+		mediaType string
+	}{
+		{"browser", []Codec{JSONCodec{}, CSVCodec{}}, browserAccept, "application/json"},
+		{"browser with xml", codecsForType(withXML, structType), browserAccept, "application/xml"},
+		{"browser with xml, map", codecsForType(withXML, mapType), browserAccept, "application/json"},
+		{"json suffix", withXML, "application/problem+json", "application/json"},
+		{"xml suffix", withXML, "application/atom+xml", ""},
+	} {
+		mediaType, codec := negotiateCodec(tc.codecs, "application/json", JSONCodec{}, tc.accept)
+		if mediaType != tc.mediaType {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.mediaType, mediaType)
+		}
+		if (codec == nil) != (tc.mediaType == "") {
+			t.Errorf("%s: unexpected codec %T", tc.name, codec)
+		}
+		if codec != nil && codec.MediaTypes()[0] != mediaType {
+			t.Errorf("%s: %q is not the media type of %T", tc.name, mediaType, codec)
+		}
+	}
+}
+
+type codecBodyReq struct {
+	Body struct {
+		Title string `json:"title" xml:"title"`
+	} `ezapi:"body"`
+}
+
+func TestBodyCodecSelection(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		opts        []HandlerOpt
+		contentType string
+		body        string
+		status      int
+	}{
+		{"json", nil, "application/json; charset=utf-8", `{"title":"a"}`, http.StatusOK},
+		{"no content type", nil, "", `{"title":"a"}`, http.StatusOK},
+		{"xml", []HandlerOpt{Codecs(XMLCodec{})}, "application/xml", `<x><title>a</title></x>`, http.StatusOK},
+		{"xml not registered", nil, "application/xml", `<x><title>a</title></x>`, http.StatusUnsupportedMediaType},
+		{"text", []HandlerOpt{Codecs(XMLCodec{})}, "text/plain", "a", http.StatusUnsupportedMediaType},
+		{"malformed", nil, "application/", `{"title":"a"}`, http.StatusUnsupportedMediaType},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+		if tc.contentType != "" {
+			r.Header.Set("Content-Type", tc.contentType)
+		}
+		req, w := bindRequest[codecBodyReq](t, r, tc.opts...)
+		if w.Code != tc.status {
+			t.Errorf("%s: expected %d, got %d: %s", tc.name, tc.status, w.Code, w.Body)
+			continue
+		}
+		if tc.status == http.StatusOK && req.Body.Title != "a" {
+			t.Errorf("%s: expected the decoded title, got %q", tc.name, req.Body.Title)
+		}
+	}
+}
+
+func TestUnsupportedMediaTypeConstructor(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a"))
+	r.Header.Set("Content-Type", "text/plain")
+	_, w := bindRequest[codecBodyReq](t, r, UnsupportedMediaTypeErrorConstructor(func(contentType string) RespError {
+		return NewProblem(http.StatusUnsupportedMediaType, "cannot decode "+contentType)
+	}))
+	if w.Code != http.StatusUnsupportedMediaType || !strings.Contains(w.Body.String(), "cannot decode text/plain") {
+		t.Errorf("expected the custom 415, got %d: %s", w.Code, w.Body)
+	}
+}
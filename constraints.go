@@ -0,0 +1,302 @@
+package ezapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ic-it/ezapi/bind"
+)
+
+const (
+	// tag values for constraints
+	_EZAPI_TAG_MIN     = "min"
+	_EZAPI_TAG_MAX     = "max"
+	_EZAPI_TAG_MIN_LEN = "minLen"
+	_EZAPI_TAG_MAX_LEN = "maxLen"
+	_EZAPI_TAG_PATTERN = "pattern"
+	_EZAPI_TAG_ENUM    = "enum"
+	_EZAPI_TAG_FORMAT  = "format"
+
+	// supported formats
+	_EZAPI_FORMAT_EMAIL = "email"
+	_EZAPI_FORMAT_UUID  = "uuid"
+	_EZAPI_FORMAT_URI   = "uri"
+)
+
+// constraints of the field, compiled from the tag values
+type fieldConstraints struct {
+	min     *float64
+	max     *float64
+	minLen  *int
+	maxLen  *int
+	pattern *regexp.Regexp
+	enum    []string
+	format  string
+}
+
+// helper function to parse the constraint tag value.
+// Returns false if the name is not a constraint
+func parseConstraint(c *fieldConstraints, name, value string) (bool, error) {
+	switch name {
+	case _EZAPI_TAG_MIN, _EZAPI_TAG_MAX:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return true, errors.Join(ErrInvalidTag, fmt.Errorf("%s should be a number, got '%s'", name, value))
+		}
+		if name == _EZAPI_TAG_MIN {
+			c.min = &n
+		} else {
+			c.max = &n
+		}
+	case _EZAPI_TAG_MIN_LEN, _EZAPI_TAG_MAX_LEN:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return true, errors.Join(ErrInvalidTag, fmt.Errorf("%s should be a non-negative integer, got '%s'", name, value))
+		}
+		if name == _EZAPI_TAG_MIN_LEN {
+			c.minLen = &n
+		} else {
+			c.maxLen = &n
+		}
+	case _EZAPI_TAG_PATTERN:
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return true, errors.Join(ErrInvalidTag, fmt.Errorf("invalid pattern '%s'", value), err)
+		}
+		c.pattern = re
+	case _EZAPI_TAG_ENUM:
+		c.enum = strings.Split(value, "|")
+	case _EZAPI_TAG_FORMAT:
+		switch value {
+		case _EZAPI_FORMAT_EMAIL, _EZAPI_FORMAT_UUID, _EZAPI_FORMAT_URI:
+			c.format = value
+		default:
+			return true, errors.Join(ErrInvalidTag, fmt.Errorf("unknown format '%s'", value))
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// helper function to parse the constraints of the body struct field tag
+func parseConstraints(tag string) (*fieldConstraints, string, []error) {
+	var errs []error
+	var description string
+	c := &fieldConstraints{}
+	hasConstraints := false
+	for _, tagValue := range splitTag(tag) {
+		if tagValue == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(tagValue, "=")
+		if !ok || name == "" || value == "" {
+			errs = append(errs, errors.Join(ErrInvalidTag, fmt.Errorf("should be in the format key=value, got '%s'", tagValue)))
+			continue
+		}
+		if name == _EZAPI_TAG_DESC {
+			description = value
+			continue
+		}
+		handled, err := parseConstraint(c, name, value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !handled {
+			errs = append(errs, errors.Join(ErrInvalidTag, fmt.Errorf("unknown tag value '%s'", name)))
+			continue
+		}
+		hasConstraints = true
+	}
+	if !hasConstraints {
+		return nil, description, errs
+	}
+	return c, description, errs
+}
+
+// check the constraints kinds against the field type
+func (c *fieldConstraints) validateType(t reflect.Type) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	isList := t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+	elem := t
+	if isList {
+		elem = t.Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+	}
+	if (c.min != nil || c.max != nil) && !isNumberKind(elem.Kind()) {
+		return fmt.Errorf("min/max constraints need a number, got %s", elem)
+	}
+	if (c.minLen != nil || c.maxLen != nil) && !isList && elem.Kind() != reflect.String {
+		return fmt.Errorf("minLen/maxLen constraints need a string or a slice, got %s", t)
+	}
+	if (c.pattern != nil || c.format != "") && elem.Kind() != reflect.String {
+		return fmt.Errorf("pattern/format constraints need a string, got %s", elem)
+	}
+	return nil
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// check the value against the constraints
+func (c *fieldConstraints) check(v reflect.Value) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		if err := c.checkLen(v.Len()); err != nil {
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := c.checkValue(v.Index(i)); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	if v.Kind() == reflect.String {
+		if err := c.checkLen(len([]rune(v.String()))); err != nil {
+			return err
+		}
+	}
+	return c.checkValue(v)
+}
+
+func (c *fieldConstraints) checkLen(n int) error {
+	if c.minLen != nil {
+		if err := bind.CheckMinLen(n, *c.minLen); err != nil {
+			return err
+		}
+	}
+	if c.maxLen != nil {
+		if err := bind.CheckMaxLen(n, *c.maxLen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check the single value, the length is checked by the caller
+func (c *fieldConstraints) checkValue(v reflect.Value) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if c.min != nil || c.max != nil {
+		var n float64
+		switch {
+		case v.CanInt():
+			n = float64(v.Int())
+		case v.CanUint():
+			n = float64(v.Uint())
+		case v.CanFloat():
+			n = v.Float()
+		}
+		if c.min != nil {
+			if err := bind.CheckMin(n, *c.min); err != nil {
+				return err
+			}
+		}
+		if c.max != nil {
+			if err := bind.CheckMax(n, *c.max); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(c.enum) > 0 {
+		if err := bind.CheckEnum(fmt.Sprint(v.Interface()), c.enum...); err != nil {
+			return err
+		}
+	}
+
+	if v.Kind() != reflect.String {
+		return nil
+	}
+	s := v.String()
+
+	if c.pattern != nil {
+		if err := bind.CheckPattern(c.pattern, s); err != nil {
+			return err
+		}
+	}
+	return bind.CheckFormat(c.format, s)
+}
+
+// helper function to reflect the body struct fields with their constraints,
+// nil for the fields without them. The fields are addressed by their json names
+func reflectBodyFields(t reflect.Type) ([]reflectedKeyVal, []ReflectError) {
+	var errs []ReflectError
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	var fields []reflectedKeyVal
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get(_EZAPI_TAG_NAME)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && tag == "" {
+			// not in the body
+			continue
+		}
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+
+		var constraints *fieldConstraints
+		var description string
+		if tag != "" {
+			var tagErrs []error
+			constraints, description, tagErrs = parseConstraints(tag)
+			for _, err := range tagErrs {
+				errs = append(errs, ReflectError{Field: field.Name, Tag: tag, Err: err})
+			}
+			if constraints != nil {
+				if err := constraints.validateType(field.Type); err != nil {
+					errs = append(errs, ReflectError{Field: field.Name, Tag: tag, Err: errors.Join(ErrInvalidTag, err)})
+					continue
+				}
+			}
+		}
+
+		fields = append(fields, reflectedKeyVal{
+			typ:         field.Type,
+			fieldName:   field.Name,
+			index:       field.Index,
+			alias:       name,
+			aliasIsSet:  true,
+			description: description,
+			constraints: constraints,
+		})
+	}
+	return fields, errs
+}
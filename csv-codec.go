@@ -0,0 +1,194 @@
+package ezapi
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// CSV codec for slices of structs and [][]string. The first row is the header
+// with the json names of the fields
+type CSVCodec struct{}
+
+func (CSVCodec) MediaTypes() []string {
+	return []string{"text/csv"}
+}
+
+func (CSVCodec) Supports(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	elem := t.Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct || elem == reflect.TypeOf([]string{})
+}
+
+func (c CSVCodec) Decode(r io.Reader, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || !c.Supports(rv.Type()) {
+		return errors.Join(ErrorUnsuppType, fmt.Errorf("csv: cannot decode into %T", v))
+	}
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	slice := rv.Elem()
+	for slice.Kind() == reflect.Ptr {
+		if slice.IsNil() {
+			slice.Set(reflect.New(slice.Type().Elem()))
+		}
+		slice = slice.Elem()
+	}
+	if slice.Type().Elem() == reflect.TypeOf([]string{}) {
+		slice.Set(reflect.ValueOf(records))
+		return nil
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	elemType := slice.Type().Elem()
+	structType := elemType
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	columns := csvColumns(structType)
+	header := records[0]
+
+	// compile the converters of the known columns once
+	indexes := make([][]int, len(header))
+	converters := make([]strConverter, len(header))
+	for i, name := range header {
+		fieldIndex, ok := columns[name]
+		if !ok {
+			continue
+		}
+		convert, err := compileStrConverter(structType.FieldByIndex(fieldIndex).Type)
+		if err != nil {
+			return errors.Join(err, fmt.Errorf("csv: column %s", name))
+		}
+		indexes[i] = fieldIndex
+		converters[i] = convert
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(records)-1)
+	for _, record := range records[1:] {
+		item := reflect.New(structType).Elem()
+		for i, name := range header {
+			if i >= len(record) {
+				break
+			}
+			if converters[i] == nil {
+				continue
+			}
+			value, err := converters[i](record[i])
+			if err != nil {
+				return errors.Join(err, fmt.Errorf("csv: column %s", name))
+			}
+			item.FieldByIndex(indexes[i]).Set(reflect.ValueOf(value))
+		}
+		if elemType.Kind() == reflect.Ptr {
+			item = item.Addr()
+		}
+		result = reflect.Append(result, item)
+	}
+	slice.Set(result)
+	return nil
+}
+
+func (c CSVCodec) Encode(w io.Writer, v any) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !c.Supports(rv.Type()) {
+		return errors.Join(ErrorUnsuppType, fmt.Errorf("csv: cannot encode %T", v))
+	}
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	cw := csv.NewWriter(w)
+	if records, ok := rv.Interface().([][]string); ok {
+		if err := cw.WriteAll(records); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+
+	structType := rv.Type().Elem()
+	for structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	names, indexes := csvHeader(structType)
+	if err := cw.Write(names); err != nil {
+		return err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		item := rv.Index(i)
+		for item.Kind() == reflect.Ptr {
+			item = item.Elem()
+		}
+		record := make([]string, len(indexes))
+		if item.IsValid() {
+			for j, index := range indexes {
+				record[j] = fmt.Sprint(item.FieldByIndex(index).Interface())
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// header names and field indexes of the struct, the json names are used if set.
+// Embedded structs are flattened like encoding/json does
+func csvHeader(t reflect.Type) ([]string, [][]int) {
+	var names []string
+	var indexes [][]int
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embeddedNames, embeddedIndexes := csvHeader(field.Type)
+			names = append(names, embeddedNames...)
+			for _, index := range embeddedIndexes {
+				indexes = append(indexes, append([]int{i}, index...))
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+		indexes = append(indexes, []int{i})
+	}
+	return names, indexes
+}
+
+// column name to the field index
+func csvColumns(t reflect.Type) map[string][]int {
+	names, indexes := csvHeader(t)
+	columns := make(map[string][]int, len(names))
+	for i, name := range names {
+		columns[name] = indexes[i]
+	}
+	return columns
+}
@@ -1,7 +1,7 @@
 package ezapi
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -14,12 +14,17 @@ func (e DefaultUnmarshalError) Error() string {
 	return e.Err.Error()
 }
 
+func (e DefaultUnmarshalError) Unwrap() error {
+	return e.Err
+}
+
 func (e DefaultUnmarshalError) Render(ctx BaseContext) error {
-	w := ctx.GetW()
-	w.WriteHeader(http.StatusBadRequest)
 	errorBody := EzAPIError{Message: "Error unmarshalling request: " + e.Error()}
-	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(errorBody)
+	var verrs ValidationErrors
+	if errors.As(e.Err, &verrs) {
+		errorBody.Errors = verrs.Errors
+	}
+	return writeJSON(ctx.GetW(), http.StatusBadRequest, "application/json", errorBody)
 }
 
 // Internal Server Error (500)
@@ -31,14 +36,50 @@ func (e DefaultInternalError) Error() string {
 	return e.Err.Error()
 }
 
+func (e DefaultInternalError) Unwrap() error {
+	return e.Err
+}
+
 func (e DefaultInternalError) Render(ctx BaseContext) error {
-	w := ctx.GetW()
-	w.WriteHeader(http.StatusInternalServerError)
 	errorBody := EzAPIError{Message: "Internal server error: " + e.Error()}
-	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(errorBody)
+	var opaque OpaqueError
+	if errors.As(e.Err, &opaque) {
+		errorBody = EzAPIError{Message: "Internal server error", CorrelationID: opaque.ID}
+	}
+	return writeJSON(ctx.GetW(), http.StatusInternalServerError, "application/json", errorBody)
+}
+
+// Unsupported Media Type Error (415)
+type DefaultUnsupportedMediaTypeError struct {
+	ContentType string
+}
+
+func (e DefaultUnsupportedMediaTypeError) Error() string {
+	return "unsupported media type: " + e.ContentType
+}
+
+func (e DefaultUnsupportedMediaTypeError) Render(ctx BaseContext) error {
+	errorBody := EzAPIError{Message: e.Error()}
+	return writeJSON(ctx.GetW(), http.StatusUnsupportedMediaType, "application/json", errorBody)
+}
+
+// Not Acceptable Error (406)
+type DefaultNotAcceptableError struct {
+	Accept string
+}
+
+func (e DefaultNotAcceptableError) Error() string {
+	return "not acceptable: " + e.Accept
+}
+
+func (e DefaultNotAcceptableError) Render(ctx BaseContext) error {
+	errorBody := EzAPIError{Message: e.Error()}
+	return writeJSON(ctx.GetW(), http.StatusNotAcceptable, "application/json", errorBody)
 }
 
 type EzAPIError struct {
-	Message string `json:"message"`
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors,omitempty"`
+	// Set for the internal errors in the production mode, see ProductionErrors
+	CorrelationID string `json:"correlationId,omitempty"`
 }
@@ -0,0 +1,169 @@
+package ezapi
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrorRegistry maps plain Go errors to problems, so the domain packages
+// don't have to implement RespError. See E and ResolveErrors
+type ErrorRegistry struct {
+	mu       sync.RWMutex
+	mappings []errorMapping
+}
+
+// error mapping, the first matching one is used
+type errorMapping struct {
+	status  int
+	problem func(error) (Problem, bool)
+}
+
+func NewErrorRegistry() *ErrorRegistry {
+	return &ErrorRegistry{}
+}
+
+// Map maps the errors matching the target with errors.Is to the problem.
+// The message of the target is the Detail of the problem if it doesn't set
+// one, the wrapping errors are never rendered
+func (r *ErrorRegistry) Map(target error, problem Problem) {
+	r.add(problem.status(), func(err error) (Problem, bool) {
+		if !errors.Is(err, target) {
+			return Problem{}, false
+		}
+		return problemOf(problem, target, err), true
+	})
+}
+
+// MapStatus maps the errors matching the target with errors.Is to the status
+func (r *ErrorRegistry) MapStatus(target error, status int) {
+	r.Map(target, Problem{Status: status})
+}
+
+// MapType maps the errors of the type E found with errors.As to the problem.
+// The message of the found E is the Detail if the problem doesn't set one
+func MapType[E error](r *ErrorRegistry, problem Problem) {
+	r.add(problem.status(), func(err error) (Problem, bool) {
+		var target E
+		if !errors.As(err, &target) {
+			return Problem{}, false
+		}
+		return problemOf(problem, target, err), true
+	})
+}
+
+// MapTypeFunc maps the errors of the type E found with errors.As to the
+// problem built from the error, e.g. with its fields as extensions.
+// The status is used if the problem doesn't set one, the message of the
+// found E if it doesn't set the Detail
+func MapTypeFunc[E error](r *ErrorRegistry, status int, problem func(E) Problem) {
+	r.add(status, func(err error) (Problem, bool) {
+		var target E
+		if !errors.As(err, &target) {
+			return Problem{}, false
+		}
+		p := problem(target)
+		if p.Status == 0 {
+			p.Status = status
+		}
+		return problemOf(p, target, err), true
+	})
+}
+
+// Resolve returns the problem of the first mapping matching the error
+func (r *ErrorRegistry) Resolve(err error) (Problem, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, mapping := range r.mappings {
+		if problem, ok := mapping.problem(err); ok {
+			return problem, true
+		}
+	}
+	return Problem{}, false
+}
+
+func (r *ErrorRegistry) add(status int, problem func(error) (Problem, bool)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mappings = append(r.mappings, errorMapping{status: status, problem: problem})
+}
+
+// documented error responses of the mappings
+func (r *ErrorRegistry) routeErrors() []routeError {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var errs []routeError
+	seen := map[int]bool{}
+	for _, mapping := range r.mappings {
+		if !seen[mapping.status] {
+			seen[mapping.status] = true
+			errs = append(errs, routeError{status: mapping.status, typ: problemType})
+		}
+	}
+	return errs
+}
+
+// copy of the problem template for the error. The Detail defaults to the
+// message of the matched error, not of the whole chain: the wrapping errors
+// may carry internal details, e.g. the failed query
+func problemOf(problem Problem, matched error, err error) Problem {
+	if problem.Detail == "" {
+		problem.Detail = matched.Error()
+	}
+	problem.Err = err
+	return problem
+}
+
+// ResolveErrors resolves the plain errors returned by the handlers adapted
+// with E through the registry. The status codes of its mappings are
+// documented as error responses, including the ones mapped after the routes
+// are registered
+func ResolveErrors(registry *ErrorRegistry) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.errorRegistries = append(o.errorRegistries, registry)
+	}
+}
+
+// E adapts the handler returning a plain error. The error is resolved
+// through the registries of ResolveErrors: a mapped error is rendered as
+// its problem, a RespError as itself and any other one as an internal error
+func E[T any, U any](handler func(Context[T]) (U, error)) func(Context[T]) (U, RespError) {
+	return func(ctx Context[T]) (U, RespError) {
+		resp, err := handler(ctx)
+		if err != nil {
+			return resp, plainError{err: err}
+		}
+		return resp, nil
+	}
+}
+
+// plain error returned by the handler adapted with E, resolved by H
+type plainError struct {
+	err error
+}
+
+func (e plainError) Error() string {
+	return e.err.Error()
+}
+
+func (e plainError) Unwrap() error {
+	return e.err
+}
+
+// rendered as an internal error outside of H
+func (e plainError) Render(ctx BaseContext) error {
+	return DefaultInternalError{Err: e.err}.Render(ctx)
+}
+
+// resolve the plain error through the registries
+func (o *handlerOpts) resolveError(err error) RespError {
+	for _, registry := range o.errorRegistries {
+		if problem, ok := registry.Resolve(err); ok {
+			return problem
+		}
+	}
+	var respErr RespError
+	if errors.As(err, &respErr) {
+		return respErr
+	}
+	return DefaultInternalError{Err: err}
+}
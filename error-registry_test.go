@@ -0,0 +1,48 @@
+package ezapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var errNoUser = errors.New("user not found")
+
+func TestMappedErrorDetail(t *testing.T) {
+	registry := NewErrorRegistry()
+	registry.MapStatus(errNoUser, http.StatusNotFound)
+
+	handler := H(E(func(Context[struct{}]) (string, error) {
+		return "", fmt.Errorf("select * from users where token='secret': %w", errNoUser)
+	}), ResolveErrors(registry), ProductionErrors())
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body)
+	}
+	var problem Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Detail != errNoUser.Error() {
+		t.Errorf("expected the detail of the mapped error only, got %q", problem.Detail)
+	}
+}
+
+func TestResolveErrorsDocumentsLaterMappings(t *testing.T) {
+	registry := NewErrorRegistry()
+	r := NewRouter()
+	GET(r, "/user", E(func(Context[struct{}]) (string, error) { return "", nil }), ResolveErrors(registry))
+	registry.MapStatus(errNoUser, http.StatusNotFound)
+
+	for _, e := range r.Routes()[0].errorResponses() {
+		if e.status == http.StatusNotFound {
+			return
+		}
+	}
+	t.Error("expected the mapping added after the registration to be documented")
+}
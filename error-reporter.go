@@ -0,0 +1,147 @@
+package ezapi
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+)
+
+// ErrorReport is an internal error of the handler, delivered to the ErrorReporter
+type ErrorReport struct {
+	// Correlation ID, rendered to the client in the production mode
+	ID string
+	// The full error, e.g. the rendering failure joined with the rendered error
+	Err error
+	// Stack where the error was wrapped with WithStack, or of the recovered
+	// panic. Nil for the other errors
+	Stack   []byte
+	Request *http.Request
+}
+
+// Errors flattens the chain of the wrapped and joined errors, depth first
+func (r ErrorReport) Errors() []error {
+	var chain []error
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		chain = append(chain, err)
+		switch err := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(err.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, err := range err.Unwrap() {
+				walk(err)
+			}
+		}
+	}
+	walk(r.Err)
+	return chain
+}
+
+// ErrorReporter receives the internal errors of the handlers, e.g. to log them
+type ErrorReporter func(ErrorReport)
+
+// ReportErrors sets the reporter of the internal errors
+func ReportErrors(reporter ErrorReporter) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.errorReporter = reporter
+	}
+}
+
+// WithStack wraps the error with the stack of the caller,
+// the report of the internal error carries it
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return stackError{err: err, stack: debug.Stack()}
+}
+
+type stackError struct {
+	err   error
+	stack []byte
+}
+
+func (e stackError) Error() string {
+	return e.err.Error()
+}
+
+func (e stackError) Unwrap() error {
+	return e.err
+}
+
+// ProductionErrors hides the internal errors from the clients: the internal
+// error constructor receives an OpaqueError with the correlation ID of the
+// report instead of the error itself. Without ReportErrors the reports go
+// to the standard logger
+func ProductionErrors() HandlerOpt {
+	return func(o *handlerOpts) {
+		o.production = true
+	}
+}
+
+// OpaqueError replaces the internal error in the production mode,
+// only its correlation ID is rendered
+type OpaqueError struct {
+	ID string
+}
+
+func (e OpaqueError) Error() string {
+	return "internal error, correlation id: " + e.ID
+}
+
+// render the error, the rendering failures are internal errors.
+// DefaultInternalError is rendered as an internal error too
+func (o *handlerOpts) renderError(ctx BaseContext, respErr RespError) {
+	if plain, ok := respErr.(plainError); ok {
+		respErr = o.resolveError(plain.err)
+	}
+	if isCommitted(ctx.GetW()) {
+		// the response is already sent, the error can only be reported
+		o.renderInternalError(ctx, respErr, respErr)
+		return
+	}
+	var internal DefaultInternalError
+	if errors.As(respErr, &internal) {
+		o.renderInternalError(ctx, internal.Err, respErr)
+		return
+	}
+	if err := respErr.Render(ctx); err != nil {
+		o.renderInternalError(ctx, err, errors.Join(err, respErr))
+	}
+}
+
+// report the internal error and render the error returned by the constructor
+// for err, or for its OpaqueError in the production mode
+func (o *handlerOpts) renderInternalError(ctx BaseContext, err error, reported error) {
+	if o.errorReporter != nil || o.production {
+		report := ErrorReport{
+			ID:      uuid.NewString(),
+			Err:     reported,
+			Request: ctx.GetR(),
+		}
+		var stacked stackError
+		if errors.As(reported, &stacked) {
+			report.Stack = stacked.stack
+		}
+		if o.errorReporter != nil {
+			o.errorReporter(report)
+		} else {
+			// the error isn't rendered either, it would be lost
+			log.Printf("ezapi: internal error serving %s %s (correlation id %s): %v",
+				report.Request.Method, report.Request.URL.Path, report.ID, report.Err)
+		}
+		if o.production {
+			err = OpaqueError{ID: report.ID}
+		}
+	}
+	if isCommitted(ctx.GetW()) {
+		return
+	}
+	o.internalErrorConstructor(err).Render(ctx)
+}
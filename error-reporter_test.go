@@ -0,0 +1,58 @@
+package ezapi
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func failingHandler(err error) func(Context[struct{}]) (string, RespError) {
+	return func(Context[struct{}]) (string, RespError) {
+		return "", DefaultInternalError{Err: err}
+	}
+}
+
+// the error is wrapped in a function of its own, so its stack tells where
+// the error was created from where it was rendered
+func newStackedError() error {
+	return WithStack(errors.New("db down"))
+}
+
+func TestReportedStack(t *testing.T) {
+	var reports []ErrorReport
+	reporter := ReportErrors(func(report ErrorReport) { reports = append(reports, report) })
+
+	H(failingHandler(newStackedError()), reporter)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	H(failingHandler(errors.New("db down")), reporter)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reports))
+	}
+	if !bytes.Contains(reports[0].Stack, []byte("newStackedError")) {
+		t.Errorf("expected the stack of the error creation, got %s", reports[0].Stack)
+	}
+	if reports[1].Stack != nil {
+		t.Errorf("expected no stack for the plain error, got %s", reports[1].Stack)
+	}
+}
+
+func TestProductionErrorsWithoutReporterLog(t *testing.T) {
+	var logged bytes.Buffer
+	output := log.Writer()
+	log.SetOutput(&logged)
+	defer log.SetOutput(output)
+
+	w := httptest.NewRecorder()
+	H(failingHandler(errors.New("db down")), ProductionErrors())(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "db down") {
+		t.Fatalf("expected an opaque 500, got %d: %s", w.Code, w.Body)
+	}
+	if !strings.Contains(logged.String(), "db down") {
+		t.Errorf("expected the hidden error to be logged, got %q", logged.String())
+	}
+}
@@ -1,22 +1,31 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 
-	"io"
-
+	"github.com/google/uuid"
+	"github.com/ic-it/ezapi"
 	"github.com/ic-it/ezapi/examples/todo"
+	"github.com/ic-it/ezapi/examples/todo/api"
 )
 
 // Simple "TODO app client" example
 
-func main() {
-	host := "http://localhost:8080"
+// The routes of the todo-server, the requests and the responses are the
+// types the server binds
+var (
+	createTodoRoute  = ezapi.Route{Method: http.MethodPost, Pattern: "/todo"}
+	getTodoRoute     = ezapi.Route{Method: http.MethodGet, Pattern: "/todo/{id}"}
+	getAllTodosRoute = ezapi.Route{Method: http.MethodGet, Pattern: "/todos"}
+	updateTodoRoute  = ezapi.Route{Method: http.MethodPut, Pattern: "/todo/{id}"}
+	deleteTodoRoute  = ezapi.Route{Method: http.MethodDelete, Pattern: "/todo/{id}"}
+)
+
+var client = ezapi.NewClient("http://localhost:8080")
 
+func main() {
 	for {
 		fmt.Println("--------------------")
 		fmt.Println("Enter command:")
@@ -36,15 +45,15 @@ func main() {
 
 		switch command {
 		case 1:
-			create(host)
+			create()
 		case 2:
-			getOne(host)
+			getOne()
 		case 3:
-			getAll(host)
+			getAll()
 		case 4:
-			updateOne(host)
+			updateOne()
 		case 5:
-			deleteOne(host)
+			deleteOne()
 		case 6:
 			return
 		default:
@@ -53,141 +62,99 @@ func main() {
 	}
 }
 
-func create(host string) {
+func create() {
 	var title, description string
 	fmt.Println("Enter title:")
 	fmt.Scanf("%s", &title)
 	fmt.Println("Enter description (optional):")
 	fmt.Scanf("%s", &description)
 
-	body := todo.BaseTodo{
-		Title:       title,
-		Description: description,
-	}
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		log.Println("Failed to marshal body")
-		return
-	}
-
-	resp, err := post(host+"/todo", bodyBytes)
-	if err != nil {
-		log.Println("Failed to create todo")
-		return
+	req := api.CreateTodoReq{
+		JSONBody: &todo.BaseTodo{
+			Title:       title,
+			Description: description,
+		},
 	}
-
-	var todoID todo.TodoIDOnly
-	err = json.Unmarshal(resp, &todoID)
+	resp, err := ezapi.Call[api.CreateTodoReq, ezapi.Response[todo.TodoIDOnly]](client, createTodoRoute, req)
 	if err != nil {
-		log.Println("Failed to unmarshal response:", string(resp))
+		log.Println("Failed to create todo:", err)
 		return
 	}
 
-	fmt.Printf("Created todo with ID: %s\n", todoID.ID)
+	fmt.Printf("Created todo with ID: %s (%s)\n", resp.Body.ID, resp.Header.Get("Location"))
 }
 
-func getOne(host string) {
-	var id string
-	fmt.Println("Enter ID:")
-	fmt.Scanf("%s", &id)
-
-	resp, err := get(host + "/todo/" + id + "/get")
-	if err != nil {
-		log.Println("Failed to get todo")
+func getOne() {
+	id, ok := scanID()
+	if !ok {
 		return
 	}
 
-	var todoObj todo.Todo
-	err = json.Unmarshal(resp, &todoObj)
+	var req api.GetTodoReq
+	req.PathParams.ID = id
+	todoObj, err := ezapi.Call[api.GetTodoReq, todo.Todo](client, getTodoRoute, req)
 	if err != nil {
-		log.Println("Failed to unmarshal response:", string(resp))
+		log.Println("Failed to get todo:", err)
 		return
 	}
 
 	fmt.Printf("Got todo: %+v\n", todoObj)
 }
 
-func getAll(host string) {
+func getAll() {
 	var title, description string
 	fmt.Println("Enter title (optional):")
 	fmt.Scanf("%s", &title)
 	fmt.Println("Enter description (optional):")
 	fmt.Scanf("%s", &description)
 
-	resp, err := get(host + "/todos?title=" + title + "&description=" + description)
-	if err != nil {
-		log.Println("Failed to get todos")
-		return
-	}
-
-	type todosResp struct {
-		Todos []todo.Todo `json:"todos"`
-	}
-	var todos todosResp
-	err = json.Unmarshal(resp, &todos)
+	var req api.GetAllTodosReq
+	req.QueryParams.Title = title
+	req.QueryParams.Description = description
+	todos, err := ezapi.Call[api.GetAllTodosReq, api.GetAllTodosRep](client, getAllTodosRoute, req)
 	if err != nil {
-		log.Println("Failed to unmarshal response:", string(resp))
+		log.Println("Failed to get todos:", err)
 		return
 	}
 
 	fmt.Printf("Got todos: %+v\n", todos)
 }
 
-func updateOne(host string) {
-	var id, newTitle, newDescription string
-	fmt.Println("Enter ID:")
-	fmt.Scanf("%s", &id)
+func updateOne() {
+	id, ok := scanID()
+	if !ok {
+		return
+	}
+	var newTitle, newDescription string
 	fmt.Println("Enter new title (optional):")
 	fmt.Scanf("%s", &newTitle)
 	fmt.Println("Enter new description (optional):")
 	fmt.Scanf("%s", &newDescription)
 
-	type updateBody struct {
-		NewTitle       string `json:"newTitle,omitempty"`
-		NewDescription string `json:"newDescription,omitempty"`
-	}
-
-	body := updateBody{
-		NewTitle:       newTitle,
-		NewDescription: newDescription,
-	}
-	bodyBytes, err := json.Marshal(body)
-	if err != nil {
-		log.Println("Failed to marshal body")
-		return
-	}
-
-	resp, err := put(host+"/todo/"+id+"/update", bodyBytes)
-	if err != nil {
-		log.Println("Failed to update todo")
-		return
-	}
-
-	var todoID todo.TodoIDOnly
-	err = json.Unmarshal(resp, &todoID)
+	var req api.UpdateTodoReq
+	req.PathParams.ID = id
+	req.JSONBody.NewTitle = newTitle
+	req.JSONBody.NewDescription = newDescription
+	todoID, err := ezapi.Call[api.UpdateTodoReq, todo.TodoIDOnly](client, updateTodoRoute, req)
 	if err != nil {
-		log.Println("Failed to unmarshal response:", string(resp))
+		log.Println("Failed to update todo:", err)
 		return
 	}
 
 	fmt.Printf("Updated todo with ID: %s\n", todoID.ID)
 }
 
-func deleteOne(host string) {
-	var id string
-	fmt.Println("* Enter ID:")
-	fmt.Scanf("%s", &id)
-
-	resp, err := delete(host + "/todo/" + id + "/delete")
-	if err != nil {
-		log.Println("Failed to delete todo")
+func deleteOne() {
+	id, ok := scanID()
+	if !ok {
 		return
 	}
 
-	var todoID todo.TodoIDOnly
-	err = json.Unmarshal(resp, &todoID)
+	var req api.DeleteTodoReq
+	req.PathParams.ID = id
+	todoID, err := ezapi.Call[api.DeleteTodoReq, todo.TodoIDOnly](client, deleteTodoRoute, req)
 	if err != nil {
-		log.Println("Failed to unmarshal response:", string(resp))
+		log.Println("Failed to delete todo:", err)
 		return
 	}
 
@@ -195,48 +162,14 @@ func deleteOne(host string) {
 }
 
 // Helper functions
-var client = &http.Client{}
-
-func post(url string, body []byte) ([]byte, error) {
-	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	return io.ReadAll(io.Reader(resp.Body))
-}
-
-func get(url string) ([]byte, error) {
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	return io.ReadAll(io.Reader(resp.Body))
-}
-
-func put(url string, body []byte) ([]byte, error) {
-	req, err := http.NewRequest("PUT", url, bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	return io.ReadAll(io.Reader(resp.Body))
-}
-
-func delete(url string) ([]byte, error) {
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := client.Do(req)
+func scanID() (uuid.UUID, bool) {
+	var id string
+	fmt.Println("Enter ID:")
+	fmt.Scanf("%s", &id)
+	parsed, err := uuid.Parse(id)
 	if err != nil {
-		return nil, err
+		log.Println("Invalid ID:", err)
+		return uuid.UUID{}, false
 	}
-	defer resp.Body.Close()
-	return io.ReadAll(io.Reader(resp.Body))
+	return parsed, true
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/ic-it/ezapi"
 	"github.com/ic-it/ezapi/examples/todo"
+	"github.com/ic-it/ezapi/examples/todo/api"
 )
 
 // Simple "TODO app" example
@@ -16,118 +17,139 @@ import (
 var todos map[uuid.UUID]todo.Todo = make(map[uuid.UUID]todo.Todo)
 
 func main() {
-	mux := http.NewServeMux()
+	r := ezapi.NewRouter(
+		ezapi.ProblemErrors(),
+		ezapi.ProductionErrors(),
+		ezapi.ReportErrors(func(report ezapi.ErrorReport) {
+			log.Println("internal error", report.ID, report.Err)
+		}),
+		ezapi.OnPanic(func(report ezapi.PanicReport) {
+			log.Printf("panic: %v\n%s", report.Value, report.Stack)
+		}),
+	)
+	// the domain errors of the todo package are mapped to the problems
+	todoErrors := ezapi.NewErrorRegistry()
+	ezapi.MapTypeFunc(todoErrors, http.StatusNotFound, func(err todo.NotFoundError) ezapi.Problem {
+		return ezapi.Problem{Title: "Todo not found"}.WithExtension("id", err.ID)
+	})
+	todoGroup := r.Group("/todo", ezapi.ResolveErrors(todoErrors))
 
 	// Create
-	mux.HandleFunc(
-		"/todo",
-		ezapi.H(
-			func(ctx ezapi.Context[CreateTodoReq]) (todo.TodoIDOnly, ezapi.RespError) {
-				req := ctx.GetReq()
-				log.Println("create-todo", req.JSONBody)
-				newTodo := todo.Todo{
-					TodoIDOnly: todo.TodoIDOnly{ID: uuid.New()},
-					BaseTodo:   *req.JSONBody,
-				}
-				todos[newTodo.ID] = newTodo
-				log.Println("newTodo", newTodo)
-				return todo.TodoIDOnly{ID: newTodo.ID}, nil
-			},
-		))
+	ezapi.POST(
+		todoGroup, "",
+		func(ctx ezapi.Context[api.CreateTodoReq]) (ezapi.Response[todo.TodoIDOnly], ezapi.RespError) {
+			req := ctx.GetReq()
+			log.Println("create-todo", req.JSONBody)
+			newTodo := todo.Todo{
+				TodoIDOnly: todo.TodoIDOnly{ID: uuid.New()},
+				BaseTodo:   *req.JSONBody,
+			}
+			todos[newTodo.ID] = newTodo
+			log.Println("newTodo", newTodo)
+			return ezapi.Created("/todo/"+newTodo.ID.String(), todo.TodoIDOnly{ID: newTodo.ID}), nil
+		},
+		ezapi.SuccessStatus(http.StatusCreated),
+	)
 
 	// Get
-	mux.HandleFunc(
-		"/todo/{id}/get",
-		ezapi.H(
-			func(ctx ezapi.Context[GetTodoReq]) (*todo.Todo, ezapi.RespError) {
-				req := ctx.GetReq()
-				log.Println("get-todo", req.PathParams)
-				todo, ok := todos[req.PathParams.ID]
-				if !ok {
-					return nil, TodoNotFoundError{ID: req.PathParams.ID}
-				}
-				log.Println("todo", todo)
-				return &todo, nil
-			},
-		))
+	ezapi.GET(
+		todoGroup, "/{id}",
+		ezapi.E(func(ctx ezapi.Context[api.GetTodoReq]) (*todo.Todo, error) {
+			req := ctx.GetReq()
+			log.Println("get-todo", req.PathParams)
+			found, ok := todos[req.PathParams.ID]
+			if !ok {
+				return nil, todo.NotFoundError{ID: req.PathParams.ID}
+			}
+			log.Println("todo", found)
+			return &found, nil
+		}),
+	)
 
 	// Get all
-	mux.HandleFunc(
-		"/todos",
-		ezapi.H(
-			func(ctx ezapi.Context[GetAllTodosReq]) (GetAllTodosRep, ezapi.RespError) {
-				req := ctx.GetReq()
-				log.Println("get-all-todos", req.QueryParams)
-				var filteredTodos []todo.Todo
-				for _, todo := range todos {
-					if req.QueryParams.Title != "" && !strings.Contains(todo.Title, req.QueryParams.Title) {
-						continue
-					}
-					if req.QueryParams.Description != "" && !strings.Contains(todo.Description, req.QueryParams.Description) {
-						continue
-					}
-					filteredTodos = append(filteredTodos, todo)
+	ezapi.GET(
+		r, "/todos",
+		func(ctx ezapi.Context[api.GetAllTodosReq]) (api.GetAllTodosRep, ezapi.RespError) {
+			req := ctx.GetReq()
+			log.Println("get-all-todos", req.QueryParams)
+			var filteredTodos []todo.Todo
+			for _, todo := range todos {
+				if req.QueryParams.Title != "" && !strings.Contains(todo.Title, req.QueryParams.Title) {
+					continue
 				}
-				log.Println("filteredTodos", filteredTodos)
-				return GetAllTodosRep{Todos: filteredTodos}, nil
-			},
-		))
+				if req.QueryParams.Description != "" && !strings.Contains(todo.Description, req.QueryParams.Description) {
+					continue
+				}
+				filteredTodos = append(filteredTodos, todo)
+			}
+			log.Println("filteredTodos", filteredTodos)
+			return api.GetAllTodosRep{Todos: filteredTodos}, nil
+		},
+	)
 
 	// Update
-	mux.HandleFunc(
-		"/todo/{id}/update",
-		ezapi.H(
-			func(ctx ezapi.Context[UpdateTodoReq]) (*todo.TodoIDOnly, ezapi.RespError) {
-				req := ctx.GetReq()
-				log.Println("update-todo", req.PathParams, req.JSONBody)
-				updTodo, ok := todos[req.PathParams.ID]
-				if !ok {
-					return nil, TodoNotFoundError{ID: req.PathParams.ID}
-				}
-				if req.JSONBody.NewTitle != "" {
-					updTodo.Title = req.JSONBody.NewTitle
-				}
-				if req.JSONBody.NewDescription != "" {
-					updTodo.Description = req.JSONBody.NewDescription
-				}
-				todos[updTodo.ID] = updTodo
-				log.Println("updTodo", updTodo)
-				return &todo.TodoIDOnly{ID: updTodo.ID}, nil
-			},
-		))
+	ezapi.PUT(
+		todoGroup, "/{id}",
+		ezapi.E(func(ctx ezapi.Context[api.UpdateTodoReq]) (*todo.TodoIDOnly, error) {
+			req := ctx.GetReq()
+			log.Println("update-todo", req.PathParams, req.JSONBody)
+			updTodo, ok := todos[req.PathParams.ID]
+			if !ok {
+				return nil, todo.NotFoundError{ID: req.PathParams.ID}
+			}
+			if req.JSONBody.NewTitle != "" {
+				updTodo.Title = req.JSONBody.NewTitle
+			}
+			if req.JSONBody.NewDescription != "" {
+				updTodo.Description = req.JSONBody.NewDescription
+			}
+			todos[updTodo.ID] = updTodo
+			log.Println("updTodo", updTodo)
+			return &todo.TodoIDOnly{ID: updTodo.ID}, nil
+		}),
+	)
 
 	// Delete
-	mux.HandleFunc(
-		"/todo/{id}/delete",
-		ezapi.H(
-			func(ctx ezapi.Context[DeleteTodoReq]) (*todo.TodoIDOnly, ezapi.RespError) {
-				req := ctx.GetReq()
-				log.Println("delete-todo", req.PathParams)
-				dTodo, ok := todos[req.PathParams.ID]
-				if !ok {
-					return nil, TodoNotFoundError{ID: req.PathParams.ID}
-				}
-				delete(todos, dTodo.ID)
-				log.Println("dTodo", dTodo)
-				return &todo.TodoIDOnly{ID: dTodo.ID}, nil
-			},
-		))
+	ezapi.DELETE(
+		todoGroup, "/{id}",
+		ezapi.E(func(ctx ezapi.Context[api.DeleteTodoReq]) (*todo.TodoIDOnly, error) {
+			req := ctx.GetReq()
+			log.Println("delete-todo", req.PathParams)
+			dTodo, ok := todos[req.PathParams.ID]
+			if !ok {
+				return nil, todo.NotFoundError{ID: req.PathParams.ID}
+			}
+			delete(todos, dTodo.ID)
+			log.Println("dTodo", dTodo)
+			return &todo.TodoIDOnly{ID: dTodo.ID}, nil
+		}),
+	)
 
 	// Echo Hello with middleware
-	mux.Handle("/hello/{name}", Middleware(
-		ezapi.H(
-			func(ctx ezapi.Context[*HelloReq]) (HelloRep, ezapi.RespError) {
-				names := []string{ctx.GetReq().PathParams.Name}
-				names = append(names, ctx.GetReq().QueryParams.Names...)
-				names = append(names, ctx.GetReq().ContextParams.Names...)
-				message := "Hello, " + strings.Join(names, ", ") + "!"
-				log.Println("hello", message)
-				return HelloRep{Message: message}, nil
-			},
-		)))
+	helloGroup := r.Group("/hello", ezapi.Use(Middleware))
+	ezapi.GET(
+		helloGroup, "/{name}",
+		func(ctx ezapi.Context[*api.HelloReq]) (api.HelloRep, ezapi.RespError) {
+			names := []string{ctx.GetReq().PathParams.Name}
+			names = append(names, ctx.GetReq().QueryParams.Names...)
+			names = append(names, ctx.GetReq().ContextParams.Names...)
+			message := "Hello, " + strings.Join(names, ", ") + "!"
+			log.Println("hello", message)
+			return api.HelloRep{Message: message}, nil
+		},
+	)
+
+	// OpenAPI document
+	r.HandleFunc("GET /openapi.json", r.OpenAPI("TODO API", "1.0.0").Handler())
+
+	// TypeScript client
+	r.HandleFunc("GET /client.ts", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(r.TypeScript())
+	})
 
 	log.Println("Listening on :8080")
-	http.ListenAndServe(":8080", mux)
+	http.ListenAndServe(":8080", r)
 }
 
 func Middleware(next http.Handler) http.Handler {
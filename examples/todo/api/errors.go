@@ -0,0 +1,20 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ic-it/ezapi"
+)
+
+// Validation error: description or title should be provided
+type TodoTitleOrDescriptionEmptyError struct{}
+
+func (e TodoTitleOrDescriptionEmptyError) Error() string {
+	return "title or description should be provided"
+}
+
+func (e TodoTitleOrDescriptionEmptyError) Render(ctx ezapi.BaseContext) error {
+	log.Println("rendering: todo title or description empty")
+	return ezapi.NewProblem(http.StatusBadRequest, e.Error()).Render(ctx)
+}
@@ -0,0 +1,165 @@
+// Code generated by ezapi gen. DO NOT EDIT.
+
+package api
+
+import (
+	"unicode/utf8"
+
+	"github.com/ic-it/ezapi"
+	"github.com/ic-it/ezapi/bind"
+)
+
+func init() {
+	ezapi.RegisterGeneratedBinder(ezapiBindCreateTodoReq, "77770dc1374549ff")
+	ezapi.RegisterGeneratedBinder(ezapiBindGetTodoReq, "56a42bb76caf9c2f")
+	ezapi.RegisterGeneratedBinder(ezapiBindGetAllTodosReq, "39ab54b2e186cfc6")
+	ezapi.RegisterGeneratedBinder(ezapiBindUpdateTodoReq, "e8832004368776c7")
+	ezapi.RegisterGeneratedBinder(ezapiBindDeleteTodoReq, "c17a1d88f08dee14")
+	ezapi.RegisterGeneratedBinder(ezapiBindHelloReq, "62e637eb00e44a83")
+}
+
+// ezapiBindCreateTodoReq binds CreateTodoReq without reflection
+func ezapiBindCreateTodoReq(in bind.Input) (CreateTodoReq, error) {
+	var req CreateTodoReq
+	var fieldErrs []ezapi.FieldError
+
+	// jsonBody
+	if err := (ezapi.JSONCodec{}).Decode(in.Body, &req.JSONBody); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("body", nil, err))
+	} else {
+		if req.JSONBody != nil {
+			if err := bind.CheckMinLen(utf8.RuneCountInString(string(req.JSONBody.Title)), 1); err != nil {
+				fieldErrs = append(fieldErrs, ezapiFieldError("body.title", req.JSONBody.Title, err))
+			}
+		}
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindGetTodoReq binds GetTodoReq without reflection
+func ezapiBindGetTodoReq(in bind.Input) (GetTodoReq, error) {
+	var req GetTodoReq
+	var fieldErrs []ezapi.FieldError
+
+	// path
+	if value := in.PathParams["id"]; value == "" {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.id", nil, ezapi.ErrMissingPathParam))
+	} else if err := bind.Text(&req.PathParams.ID, value); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.id", value, err))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindGetAllTodosReq binds GetAllTodosReq without reflection
+func ezapiBindGetAllTodosReq(in bind.Input) (GetAllTodosReq, error) {
+	var req GetAllTodosReq
+	var fieldErrs []ezapi.FieldError
+
+	// query
+	if values := in.QueryParams["title"]; bind.NoValue(values) {
+		// optional
+	} else if err := bind.String(&req.QueryParams.Title, values[0]); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("query.title", values, err))
+	}
+	if values := in.QueryParams["description"]; bind.NoValue(values) {
+		// optional
+	} else if err := bind.String(&req.QueryParams.Description, values[0]); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("query.description", values, err))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindUpdateTodoReq binds UpdateTodoReq without reflection
+func ezapiBindUpdateTodoReq(in bind.Input) (UpdateTodoReq, error) {
+	var req UpdateTodoReq
+	var fieldErrs []ezapi.FieldError
+
+	// jsonBody
+	if err := (ezapi.JSONCodec{}).Decode(in.Body, &req.JSONBody); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("body", nil, err))
+	}
+
+	// path
+	if value := in.PathParams["id"]; value == "" {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.id", nil, ezapi.ErrMissingPathParam))
+	} else if err := bind.Text(&req.PathParams.ID, value); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.id", value, err))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindDeleteTodoReq binds DeleteTodoReq without reflection
+func ezapiBindDeleteTodoReq(in bind.Input) (DeleteTodoReq, error) {
+	var req DeleteTodoReq
+	var fieldErrs []ezapi.FieldError
+
+	// path
+	if value := in.PathParams["id"]; value == "" {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.id", nil, ezapi.ErrMissingPathParam))
+	} else if err := bind.Text(&req.PathParams.ID, value); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.id", value, err))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindHelloReq binds HelloReq without reflection
+func ezapiBindHelloReq(in bind.Input) (HelloReq, error) {
+	var req HelloReq
+	var fieldErrs []ezapi.FieldError
+
+	// path
+	bind.Alloc(&req.PathParams)
+	if value := in.PathParams["name"]; value == "" {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.name", nil, ezapi.ErrMissingPathParam))
+	} else if err := bind.String(&req.PathParams.Name, value); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.name", value, err))
+	}
+
+	// query
+	if values := in.QueryParams["name"]; len(values) == 0 {
+		fieldErrs = append(fieldErrs, ezapiFieldError("query.name", nil, ezapi.ErrMissingQueryParam))
+	} else if err := bind.Slice(&req.QueryParams.Names, values, bind.String); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("query.name", values, err))
+	}
+
+	// context
+	if value := in.ContextValues["names"]; value == nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("context.names", nil, ezapi.ErrMissingContextValue))
+	} else if s, ok := value.(string); ok && !bind.Value(&req.ContextParams.Names, value) {
+		if err := bind.Slice(&req.ContextParams.Names, []string{s}, bind.String); err != nil {
+			fieldErrs = append(fieldErrs, ezapiFieldError("context.names", s, err))
+		}
+	} else if !bind.Value(&req.ContextParams.Names, value) {
+		fieldErrs = append(fieldErrs, ezapiFieldError("context.names", nil, ezapi.ErrTypeMismatch))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// the binding failure of the field at the location
+func ezapiFieldError(location string, value any, err error) ezapi.FieldError {
+	return ezapi.FieldError{Location: location, Reason: err.Error(), Value: value, Err: err}
+}
@@ -1,7 +1,10 @@
-package main
+package api
+
+//go:generate go run github.com/ic-it/ezapi/cmd/ezapi gen
 
 import (
 	"log"
+	"net/http"
 
 	"github.com/google/uuid"
 	"github.com/ic-it/ezapi"
@@ -13,14 +16,6 @@ type CreateTodoReq struct {
 	JSONBody *todo.BaseTodo `ezapi:"jsonBody"`
 }
 
-func (req CreateTodoReq) Validate(ctx ezapi.BaseContext) ezapi.RespError {
-	log.Println("validating: create todo")
-	if req.JSONBody.Title == "" {
-		return TodoTitleEmptyError{}
-	}
-	return nil
-}
-
 // Get
 type GetTodoReq struct {
 	PathParams struct {
@@ -98,5 +93,5 @@ type HelloRep struct {
 
 func (req HelloReq) OnUnmarshalError(ctx ezapi.BaseContext, err error) ezapi.RespError {
 	log.Println("unmarshalling error: hello, ", err)
-	return ezapi.DefaultUnmarshalError{Err: err}
+	return ezapi.ProblemFromError(http.StatusBadRequest, err)
 }
@@ -3,7 +3,7 @@ package todo
 import "github.com/google/uuid"
 
 type BaseTodo struct {
-	Title       string `json:"title"`
+	Title       string `json:"title" ezapi:"minLen=1,desc=The title of the todo"`
 	Description string `json:"description,omitempty"`
 }
 
@@ -15,3 +15,12 @@ type Todo struct {
 	TodoIDOnly
 	BaseTodo
 }
+
+// NotFoundError is returned when there is no todo with the ID
+type NotFoundError struct {
+	ID uuid.UUID
+}
+
+func (e NotFoundError) Error() string {
+	return "todo not found"
+}
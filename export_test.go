@@ -0,0 +1,16 @@
+package ezapi
+
+import "reflect"
+
+// UnregisterBinder drops the binder of the request struct, so H binds it
+// by reflection again
+func UnregisterBinder[T any]() {
+	bindersMu.Lock()
+	defer bindersMu.Unlock()
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	delete(binders, t)
+	delete(binders, reflect.PointerTo(t))
+}
@@ -1,22 +1,66 @@
 package ezapi
 
 import (
-	"encoding/json"
+	"bytes"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"reflect"
 )
 
 type handlerOpts struct {
 	//
 	contentType                      string
 	defaultUnmarshalErrorConstructor func(error) RespError
+	internalErrorConstructor         func(error) RespError
+	multipartMaxMemory               int64
+	successStatus                    int
+	codecs                           []Codec
+
+	unsupportedMediaTypeErrorConstructor func(contentType string) RespError
+	notAcceptableErrorConstructor        func(accept string) RespError
+
+	// the default errors are rendered as problems, see ProblemErrors
+	problems bool
+
+	// internal errors, see ErrorReport
+	errorReporter ErrorReporter
+	production    bool
+
+	// recovered panics, see PanicReport. Without a constructor an opaque
+	// internal error is rendered
+	panicErrorConstructor func(value any) RespError
+	panicObserver         func(PanicReport)
+
+	// plain errors of the handlers adapted with E, see ResolveErrors
+	errorRegistries []*ErrorRegistry
+
+	// documented error responses, see ErrorResponse
+	errorResponses []routeError
+
+	// called with the route metadata once the handler is built
+	routeHooks []func(Route)
+
+	// applied in the order they were added, the first one is the outermost
+	middlewares []Middleware
 }
 
 func newHandlerOpts() *handlerOpts {
 	return &handlerOpts{
-		contentType: "application/json",
+		contentType:        "application/json",
+		multipartMaxMemory: 32 << 20, // same as net/http
 		defaultUnmarshalErrorConstructor: func(err error) RespError {
 			return DefaultUnmarshalError{Err: err}
 		},
+		internalErrorConstructor: func(err error) RespError {
+			return DefaultInternalError{Err: err}
+		},
+		unsupportedMediaTypeErrorConstructor: func(contentType string) RespError {
+			return DefaultUnsupportedMediaTypeError{ContentType: contentType}
+		},
+		notAcceptableErrorConstructor: func(accept string) RespError {
+			return DefaultNotAcceptableError{Accept: accept}
+		},
 	}
 }
 
@@ -28,23 +72,187 @@ func ContentType(contentType string) HandlerOpt {
 	}
 }
 
+// SuccessStatus sets the status code of the successful responses,
+// a Response with a non-zero Status overrides it. It is 200 by default and
+// 204 for the handlers without a body, e.g. returning NoContent.
+// The status set at runtime isn't documented, e.g. the 201 of Created
+// needs SuccessStatus(http.StatusCreated) to appear in the OpenAPI spec
+func SuccessStatus(status int) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.successStatus = status
+	}
+}
+
 func DefaultUnmarshalErrorConstructor(constructor func(error) RespError) HandlerOpt {
 	return func(o *handlerOpts) {
 		o.defaultUnmarshalErrorConstructor = constructor
 	}
 }
 
+// InternalErrorConstructor sets the error rendered when rendering
+// the response or another error fails
+func InternalErrorConstructor(constructor func(error) RespError) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.internalErrorConstructor = constructor
+	}
+}
+
+func UnsupportedMediaTypeErrorConstructor(constructor func(contentType string) RespError) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.unsupportedMediaTypeErrorConstructor = constructor
+	}
+}
+
+func NotAcceptableErrorConstructor(constructor func(accept string) RespError) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.notAcceptableErrorConstructor = constructor
+	}
+}
+
+// ErrorResponse documents an error response of the handler with the body
+// of type E, e.g. ErrorResponse[EzAPIError](http.StatusNotFound).
+// It is used by the OpenAPI spec and the generated clients only
+func ErrorResponse[E any](status int) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.errorResponses = append(o.errorResponses, routeError{
+			status: status,
+			typ:    reflect.TypeOf((*E)(nil)).Elem(),
+		})
+	}
+}
+
+// MultipartMaxMemory sets how many bytes of the multipart form are kept
+// in memory, the rest of the files is stored on disk
+func MultipartMaxMemory(maxMemory int64) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.multipartMaxMemory = maxMemory
+	}
+}
+
+// Middleware wraps the handler built by H
+type Middleware func(http.Handler) http.Handler
+
+// Use adds middlewares around the handler
+func Use(middlewares ...Middleware) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// wrap the handler with the middlewares
+func (o *handlerOpts) wrap(handler http.Handler) http.Handler {
+	for i := len(o.middlewares) - 1; i >= 0; i-- {
+		handler = o.middlewares[i](handler)
+	}
+	return handler
+}
+
+// validator of the request section
+type sectionValidator struct {
+	location string
+	cb       func(any, BaseContext) RespError
+}
+
+// internal option to receive the route metadata
+func onRoute(hook func(Route)) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.routeHooks = append(o.routeHooks, hook)
+	}
+}
+
 func H[T any, U any](handler func(Context[T]) (U, RespError), opts ...HandlerOpt) http.HandlerFunc {
+	handlerFunc, err := TryH(handler, opts...)
+	if err != nil {
+		panicReflectErrors(err)
+	}
+	return handlerFunc
+}
+
+// TryH builds the handler like H, but returns the misconfigurations of the
+// request struct as ReflectErrors instead of panicking
+func TryH[T any, U any](handler func(Context[T]) (U, RespError), opts ...HandlerOpt) (http.HandlerFunc, error) {
 	options := newHandlerOpts()
 
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	reflected := ReflectReq[T]()
-	unmarshler := BuildUnmarshaler[T](reflected)
+	reflected, err := reflectReq[T]()
+	if err != nil {
+		return nil, err
+	}
+	// the binder generated by `ezapi gen` replaces the reflection based unmarshaler
+	var unmarshler unmarshaler[T]
+	if binder, ok := lookupBinder[T](); ok {
+		unmarshler = binder.unmarshaler()
+	} else {
+		unmarshler = BuildUnmarshaler[T](reflected)
+	}
+	codecs := options.allCodecs()
+	bodyCodecs := codecs
+	if reflected.hasBody() {
+		bodyCodecs = codecsForType(codecs, reflected.bodyType)
+	}
 
-	return func(w http.ResponseWriter, r *http.Request) {
+	// the response is encoded with the codec negotiated from the Accept header,
+	// unless it is a string or renders itself
+	respType := reflect.TypeOf((*U)(nil)).Elem()
+	var zeroResp U
+	if envelope, ok := any(zeroResp).(responseEnvelope); ok {
+		respType = envelope.envelopeBodyType()
+	}
+	if options.successStatus == 0 {
+		options.successStatus = http.StatusOK
+		if respType == reflect.TypeOf(struct{}{}) {
+			options.successStatus = http.StatusNoContent
+		}
+	}
+	respCodecs := codecsForType(codecs, respType)
+	negotiatesResp := respType != reflect.TypeOf("") &&
+		respType != reflect.TypeOf(struct{}{}) && // no body, e.g. NoContent
+		!respType.Implements(reflect.TypeOf((*Renderable)(nil)).Elem())
+	defaultRespCodec := codecForMediaType(respCodecs, options.contentType)
+	if defaultRespCodec == nil {
+		defaultRespCodec = JSONCodec{}
+	}
+
+	// section validators, they run before the request validator
+	var validators []sectionValidator
+	for _, validator := range []sectionValidator{
+		{location: _EZAPI_TAG_QUERY_PARAMS, cb: reflected.queryParamsValidatorCb},
+		{location: _EZAPI_TAG_PATH_PARAMS, cb: reflected.pathParamsValidatorCb},
+		{location: _EZAPI_TAG_HEADERS, cb: reflected.headersValidatorCb},
+		{location: _EZAPI_TAG_COOKIES, cb: reflected.cookiesValidatorCb},
+		{location: _EZAPI_TAG_FORM, cb: reflected.formValidatorCb},
+		{location: _EZAPI_TAG_MULTIPART, cb: reflected.multipartValidatorCb},
+		{location: _EZAPI_TAG_CONTEXT, cb: reflected.contextValidatorCb},
+		{location: _EZAPI_TAG_BODY, cb: reflected.bodyValidatorCb},
+		{location: _EZAPI_TAG_BODY, cb: reflected.jsonBodyValidatorCb},
+	} {
+		if validator.cb != nil {
+			validators = append(validators, validator)
+		}
+	}
+
+	if len(options.routeHooks) > 0 {
+		rt := Route{
+			req:         reflected,
+			respType:    respType,
+			contentType: options.contentType,
+			codecs:      codecs,
+			status:      options.successStatus,
+			errors:      options.errorResponses,
+			registries:  options.errorRegistries,
+			problems:    options.problems,
+		}
+		for _, hook := range options.routeHooks {
+			hook(rt)
+		}
+	}
+
+	handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+		// track the committed response, see responseWriter
+		w = &responseWriter{ResponseWriter: w}
 		var req T
 		var err error
 
@@ -52,130 +260,133 @@ func H[T any, U any](handler func(Context[T]) (U, RespError), opts ...HandlerOpt
 			r: r,
 			w: w,
 		}
+		defer options.recoverPanic(ctx)
 
 		qParams := map[string][]string{}
 		pParams := map[string]string{}
 		ctxVals := map[string]any{}
 
+		// collect the raw values, missing ones are reported by the unmarshaler
+		query := r.URL.Query()
 		for _, p := range reflected.queryParams {
-			vals, ok := r.URL.Query()[p.alias]
-			if !ok {
-				if !p.optional {
-					missParamErr := MissingPathParamError{Param: p.alias}
-					if oue, ok := any(req).(OnUnmarshalError); ok {
-						if err := oue.OnUnmarshalError(ctx, missParamErr); err != nil {
-							if err := err.Render(ctx); err != nil {
-								DefaultInternalError{Err: err}.Render(ctx)
-							}
-						}
-						return
-					} else {
-						err := options.defaultUnmarshalErrorConstructor(missParamErr)
-						if err := err.Render(ctx); err != nil {
-							DefaultInternalError{Err: err}.Render(ctx)
-						}
-						return
-					}
-				}
-				continue
+			if vals, ok := query[p.alias]; ok {
+				qParams[p.alias] = vals
 			}
-			qParams[p.alias] = vals
 		}
 
 		for _, p := range reflected.pathParams {
-			pp := r.PathValue(p.alias)
-			if pp == "" {
-				if !p.optional {
-					missParamErr := MissingPathParamError{Param: p.alias}
-					if oue, ok := any(req).(OnUnmarshalError); ok {
-						if err := oue.OnUnmarshalError(ctx, missParamErr); err != nil {
-							if err := err.Render(ctx); err != nil {
-								DefaultInternalError{Err: err}.Render(ctx)
-							}
-						}
-						return
-					} else {
-						err := options.defaultUnmarshalErrorConstructor(missParamErr)
-						if err := err.Render(ctx); err != nil {
-							DefaultInternalError{Err: err}.Render(ctx)
-						}
-						return
-					}
-				}
-				continue
+			if pp := r.PathValue(p.alias); pp != "" {
+				pParams[p.alias] = pp
 			}
-			pParams[p.alias] = pp
 		}
 
 		for _, p := range reflected.contextValues {
 			ctxVals[p.alias] = r.Context().Value(p.alias)
 		}
 
-		req, err = unmarshler(r.Body, pParams, qParams, ctxVals)
-		if err != nil {
-			if oue, ok := any(req).(OnUnmarshalError); ok {
-				if err := oue.OnUnmarshalError(ctx, err); err != nil {
-					if err := err.Render(ctx); err != nil {
-						DefaultInternalError{Err: err}.Render(ctx)
+		var cookies []*http.Cookie
+		if reflected.hasCookies() {
+			cookies = r.Cookies()
+		}
+
+		// parse the form bodies
+		var form url.Values
+		var multipartForm *multipart.Form
+		if reflected.hasForm() {
+			if err := r.ParseForm(); err != nil {
+				if oue, ok := any(req).(OnUnmarshalError); ok {
+					if err := oue.OnUnmarshalError(ctx, err); err != nil {
+						options.renderError(ctx, err)
 					}
+					return
+				} else {
+					options.renderError(ctx, options.defaultUnmarshalErrorConstructor(err))
+					return
 				}
-				return
-			} else {
-				err := options.defaultUnmarshalErrorConstructor(err)
-				if err := err.Render(ctx); err != nil {
-					DefaultInternalError{Err: err}.Render(ctx)
-				}
-				return
 			}
+			form = r.PostForm
 		}
-
-		// Validate the request
-		// Validate query params
-		if validatorCb := reflected.queryParamsValidatorCb; validatorCb != nil {
-			if err := validatorCb(req, ctx); err != nil {
-				if err := err.Render(ctx); err != nil {
-					DefaultInternalError{Err: err}.Render(ctx)
+		if reflected.hasMultipart() {
+			if err := r.ParseMultipartForm(options.multipartMaxMemory); err != nil {
+				if oue, ok := any(req).(OnUnmarshalError); ok {
+					if err := oue.OnUnmarshalError(ctx, err); err != nil {
+						options.renderError(ctx, err)
+					}
+					return
+				} else {
+					options.renderError(ctx, options.defaultUnmarshalErrorConstructor(err))
+					return
 				}
-				return
 			}
+			multipartForm = r.MultipartForm
 		}
 
-		// Validate path params
-		if validatorCb := reflected.pathParamsValidatorCb; validatorCb != nil {
-			if err := validatorCb(req, ctx); err != nil {
-				if err := err.Render(ctx); err != nil {
-					DefaultInternalError{Err: err}.Render(ctx)
-				}
+		// select the body codec
+		var bodyCodec Codec
+		if reflected.hasBody() {
+			contentType := r.Header.Get("Content-Type")
+			bodyCodec = codecForContentType(bodyCodecs, contentType)
+			if bodyCodec == nil {
+				options.renderError(ctx, options.unsupportedMediaTypeErrorConstructor(contentType))
 				return
 			}
 		}
 
-		// Validate context values
-		if validatorCb := reflected.contextValidatorCb; validatorCb != nil {
-			if err := validatorCb(req, ctx); err != nil {
-				if err := err.Render(ctx); err != nil {
-					DefaultInternalError{Err: err}.Render(ctx)
+		req, err = unmarshler(r.Body, pParams, qParams, ctxVals, r.Header, cookies, form, multipartForm, bodyCodec)
+		if err != nil {
+			if oue, ok := any(req).(OnUnmarshalError); ok {
+				if err := oue.OnUnmarshalError(ctx, err); err != nil {
+					options.renderError(ctx, err)
 				}
 				return
+			} else {
+				options.renderError(ctx, options.defaultUnmarshalErrorConstructor(err))
+				return
 			}
 		}
 
-		// Validate the json body
-		if validatorCb := reflected.jsonBodyValidatorCb; validatorCb != nil {
-			if err := validatorCb(req, ctx); err != nil {
-				if err := err.Render(ctx); err != nil {
-					DefaultInternalError{Err: err}.Render(ctx)
-				}
-				return
+		// Validate the request sections and the request itself,
+		// all the failures are reported together
+		var validationErrs []RespError
+		var validationLocations []string
+		for _, validator := range validators {
+			if err := validator.cb(req, ctx); err != nil {
+				validationErrs = append(validationErrs, err)
+				validationLocations = append(validationLocations, validator.location)
 			}
 		}
-
-		// Validate the request
 		if validatable, ok := any(req).(Validatable); ok {
 			if err := validatable.Validate(ctx); err != nil {
-				if err := err.Render(ctx); err != nil {
-					DefaultInternalError{Err: err}.Render(ctx)
+				validationErrs = append(validationErrs, err)
+				validationLocations = append(validationLocations, "")
+			}
+		}
+		if len(validationErrs) > 0 {
+			// an error other than a field failure renders itself,
+			// the field failures are combined into a 422
+			for _, err := range validationErrs {
+				if !isValidationError(err) {
+					options.renderError(ctx, err)
+					return
 				}
+			}
+			verrs := combineValidationErrors(validationLocations, validationErrs)
+			if options.problems {
+				options.renderError(ctx, ValidationProblem(verrs))
+			} else {
+				options.renderError(ctx, verrs)
+			}
+			return
+		}
+
+		// Negotiate the response format before the handler is called
+		respMediaType, respCodec := options.contentType, defaultRespCodec
+		if negotiatesResp {
+			w.Header().Add("Vary", "Accept")
+			accept := r.Header.Get("Accept")
+			respMediaType, respCodec = negotiateCodec(respCodecs, options.contentType, defaultRespCodec, accept)
+			if respCodec == nil {
+				options.renderError(ctx, options.notAcceptableErrorConstructor(accept))
 				return
 			}
 		}
@@ -183,27 +394,71 @@ func H[T any, U any](handler func(Context[T]) (U, RespError), opts ...HandlerOpt
 		ctx.req = req
 		resp, handleErr := handler(ctx)
 		if handleErr != nil {
-			err := handleErr.Render(ctx)
-			if err != nil {
-				DefaultInternalError{Err: err}.Render(ctx)
-			}
+			options.renderError(ctx, handleErr)
 			return
 		}
 
-		if textResp, ok := any(resp).(string); ok {
+		// Unwrap the response envelope
+		var respBody any = resp
+		var respHeader http.Header
+		status := options.successStatus
+		// the status set by the envelope replaces the one a renderable body writes
+		overridesStatus := false
+		if envelope, ok := respBody.(responseEnvelope); ok {
+			respHeader = envelope.envelopeHeader()
+			if envelope.envelopeStatus() != 0 {
+				status = envelope.envelopeStatus()
+				overridesStatus = true
+			}
+			respBody = envelope.envelopeBody()
+		}
+
+		// Encode the body before anything is sent, so an encoding failure
+		// is still rendered as a clean internal error
+		var encoded bytes.Buffer
+		_, isText := respBody.(string)
+		_, isRenderable := respBody.(Renderable)
+		if !bodylessStatus(status) && !isText && !isRenderable {
+			if err := respCodec.Encode(&encoded, respBody); err != nil {
+				options.renderInternalError(ctx, err, err)
+				return
+			}
+		}
+
+		for key, values := range respHeader {
+			w.Header()[key] = values
+		}
+		if bodylessStatus(status) {
+			w.WriteHeader(status)
+		} else if textResp, ok := respBody.(string); ok {
 			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(status)
 			w.Write([]byte(textResp))
-		} else if renderable, ok := any(resp).(Renderable); ok {
-			if err := renderable.Render(ctx); err != nil {
-				DefaultInternalError{Err: err}.Render(ctx)
+		} else if renderable, ok := respBody.(Renderable); ok {
+			renderCtx := ctx
+			if overridesStatus {
+				renderCtx.w = statusWriter{ResponseWriter: w, status: status}
 			}
-		} else {
-			w.Header().Set("Content-Type", options.contentType)
-			if err := json.NewEncoder(w).Encode(resp); err != nil {
-				DefaultInternalError{Err: err}.Render(ctx)
+			if err := renderable.Render(renderCtx); err != nil {
+				options.renderInternalError(ctx, err, err)
 			}
+		} else {
+			w.Header().Set("Content-Type", respMediaType)
+			w.WriteHeader(status)
+			w.Write(encoded.Bytes())
 		}
 	}
+
+	if len(options.middlewares) == 0 {
+		return handlerFunc, nil
+	}
+	// the panics of the middlewares are recovered too
+	wrapped := options.wrap(http.HandlerFunc(handlerFunc))
+	return func(w http.ResponseWriter, r *http.Request) {
+		w = &responseWriter{ResponseWriter: w}
+		defer options.recoverPanic(ezapiContext[T]{r: r, w: w})
+		wrapped.ServeHTTP(w, r)
+	}, nil
 }
 
 type MissingQueryParamError struct {
@@ -221,3 +476,19 @@ type MissingPathParamError struct {
 func (e MissingPathParamError) Error() string {
 	return "missing path param: " + e.Param
 }
+
+type MissingHeaderError struct {
+	Header string
+}
+
+func (e MissingHeaderError) Error() string {
+	return "missing header: " + e.Header
+}
+
+type MissingCookieError struct {
+	Cookie string
+}
+
+func (e MissingCookieError) Error() string {
+	return "missing cookie: " + e.Cookie
+}
@@ -0,0 +1,167 @@
+package ezapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type validatedReq struct {
+	Query struct {
+		Name string `ezapi:"name,optional"`
+	} `ezapi:"query"`
+}
+
+type forbiddenError struct{}
+
+func (forbiddenError) Error() string { return "forbidden" }
+
+func (e forbiddenError) Render(ctx BaseContext) error {
+	return writeJSON(ctx.GetW(), http.StatusForbidden, "application/json", EzAPIError{Message: e.Error()})
+}
+
+func (req validatedReq) Validate(BaseContext) RespError {
+	switch req.Query.Name {
+	case "":
+		return ValidationErrors{Errors: []FieldError{{Location: "query.name", Reason: "should be provided"}}}
+	case "admin":
+		return forbiddenError{}
+	}
+	return nil
+}
+
+func TestSingleValidationFailure(t *testing.T) {
+	handler := H(func(Context[validatedReq]) (string, RespError) { return "ok", nil })
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body)
+	}
+	var body EzAPIError
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Errors) != 1 || body.Errors[0].Location != "query.name" {
+		t.Errorf("expected the failure as a field error, got %+v", body)
+	}
+}
+
+func TestValidateRendersOwnError(t *testing.T) {
+	handler := H(func(Context[validatedReq]) (string, RespError) { return "ok", nil })
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/?name=admin", nil))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body)
+	}
+	var body EzAPIError
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Message != "forbidden" || len(body.Errors) != 0 {
+		t.Errorf("expected the error to render itself, got %+v", body)
+	}
+}
+
+func TestRenderableResponseStatus(t *testing.T) {
+	handler := H(func(Context[struct{}]) (Response[Problem], RespError) {
+		resp := WithStatus(http.StatusAccepted, NewProblem(http.StatusOK, "queued"))
+		resp.Header = http.Header{"X-A": {"1"}}
+		return resp, nil
+	})
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected the status of the envelope, got %d: %s", w.Code, w.Body)
+	}
+	if w.Header().Get("X-A") != "1" || w.Header().Get("Content-Type") != _PROBLEM_CONTENT_TYPE {
+		t.Errorf("expected the headers of the envelope and of the body, got %v", w.Header())
+	}
+}
+
+// the connection of the hijacked responses, writing to the writer afterwards fails
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (w *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	client, server := net.Pipe()
+	client.Close()
+	return server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), nil
+}
+
+func TestHijackCommitsResponse(t *testing.T) {
+	handler := H(func(ctx Context[struct{}]) (string, RespError) {
+		conn, _, err := http.NewResponseController(ctx.GetW()).Hijack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+		return "", NewProblem(http.StatusTeapot, "after the hijack")
+	})
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !w.hijacked {
+		t.Fatal("expected the connection to be hijacked")
+	}
+	if w.ResponseRecorder.Body.Len() != 0 || w.ResponseRecorder.Code == http.StatusTeapot {
+		t.Errorf("expected nothing rendered after the hijack, got %d: %s", w.Code, w.Body)
+	}
+}
+
+// records the pushes and the copies of the wrapped writer
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+	copied int64
+}
+
+func (w *pushRecorder) Push(target string, _ *http.PushOptions) error {
+	w.pushed = append(w.pushed, target)
+	return nil
+}
+
+func (w *pushRecorder) ReadFrom(r io.Reader) (int64, error) {
+	n, err := io.Copy(w.ResponseRecorder, r)
+	w.copied += n
+	return n, err
+}
+
+func TestResponseWriterForwards(t *testing.T) {
+	handler := H(func(ctx Context[struct{}]) (string, RespError) {
+		pusher, ok := ctx.GetW().(http.Pusher)
+		if !ok {
+			t.Fatal("expected the writer to be a http.Pusher")
+		}
+		if err := pusher.Push("/app.css", nil); err != nil {
+			t.Fatal(err)
+		}
+		readerFrom, ok := ctx.GetW().(io.ReaderFrom)
+		if !ok {
+			t.Fatal("expected the writer to be an io.ReaderFrom")
+		}
+		if _, err := readerFrom.ReadFrom(strings.NewReader("streamed")); err != nil {
+			t.Fatal(err)
+		}
+		return "", NewProblem(http.StatusTeapot, "after the copy")
+	})
+	w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(w.pushed) != 1 || w.copied != int64(len("streamed")) {
+		t.Errorf("expected the push and the copy to be forwarded, got %v and %d bytes", w.pushed, w.copied)
+	}
+	if w.Code != http.StatusOK || w.Body.String() != "streamed" {
+		t.Errorf("expected the copy to commit the response, got %d: %s", w.Code, w.Body)
+	}
+}
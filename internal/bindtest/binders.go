@@ -0,0 +1,14 @@
+package bindtest
+
+// the generated binders, compared with the reflection based unmarshaler
+var (
+	BindPath      = ezapiBindPathReq
+	BindQuery     = ezapiBindQueryReq
+	BindContext   = ezapiBindContextReq
+	BindHeader    = ezapiBindHeaderReq
+	BindCookie    = ezapiBindCookieReq
+	BindForm      = ezapiBindFormReq
+	BindMultipart = ezapiBindMultipartReq
+	BindJSONBody  = ezapiBindJSONBodyReq
+	BindBody      = ezapiBindBodyReq
+)
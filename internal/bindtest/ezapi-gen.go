@@ -0,0 +1,251 @@
+// Code generated by ezapi gen. DO NOT EDIT.
+
+package bindtest
+
+import (
+	"unicode/utf8"
+
+	"github.com/ic-it/ezapi"
+	"github.com/ic-it/ezapi/bind"
+)
+
+func init() {
+	ezapi.RegisterGeneratedBinder(ezapiBindPathReq, "4eb62b8832d72205")
+	ezapi.RegisterGeneratedBinder(ezapiBindQueryReq, "3b8ecee8d0218fcf")
+	ezapi.RegisterGeneratedBinder(ezapiBindContextReq, "a9b67ba4f7b5926e")
+	ezapi.RegisterGeneratedBinder(ezapiBindHeaderReq, "e18cce98d3dbf1d1")
+	ezapi.RegisterGeneratedBinder(ezapiBindCookieReq, "5c9a707e63d69d8")
+	ezapi.RegisterGeneratedBinder(ezapiBindFormReq, "8e3f0cff2525a1c")
+	ezapi.RegisterGeneratedBinder(ezapiBindMultipartReq, "eecff31494334e9a")
+	ezapi.RegisterGeneratedBinder(ezapiBindJSONBodyReq, "3fbf38fc078d8620")
+	ezapi.RegisterGeneratedBinder(ezapiBindBodyReq, "e74e474c2e32ba48")
+}
+
+// ezapiBindPathReq binds PathReq without reflection
+func ezapiBindPathReq(in bind.Input) (PathReq, error) {
+	var req PathReq
+	var fieldErrs []ezapi.FieldError
+
+	// path
+	if value := in.PathParams["id"]; value == "" {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.id", nil, ezapi.ErrMissingPathParam))
+	} else if err := bind.Int(&req.Path.ID, value); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.id", value, err))
+	} else if err := bind.CheckMin(float64(req.Path.ID), 1); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.id", req.Path.ID, err))
+	}
+	if value := in.PathParams["slug"]; value == "" {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.slug", nil, ezapi.ErrMissingPathParam))
+	} else if err := bind.String(&req.Path.Slug, value); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.slug", value, err))
+	} else if err := bind.CheckMinLen(utf8.RuneCountInString(string(req.Path.Slug)), 1); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("path.slug", req.Path.Slug, err))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindQueryReq binds QueryReq without reflection
+func ezapiBindQueryReq(in bind.Input) (QueryReq, error) {
+	var req QueryReq
+	var fieldErrs []ezapi.FieldError
+
+	// query
+	if values := in.QueryParams["limit"]; bind.NoValue(values) {
+		_ = bind.Int(&req.Query.Limit, "20") // the default is checked when the handler is registered
+	} else if err := bind.Int(&req.Query.Limit, values[0]); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("query.limit", values, err))
+	} else if err := bind.CheckMax(float64(req.Query.Limit), 100); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("query.limit", req.Query.Limit, err))
+	}
+	if values := in.QueryParams["tag"]; bind.NoValue(values) {
+		// optional
+	} else if err := bind.Slice(&req.Query.Tags, values, bind.String); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("query.tag", values, err))
+	}
+	if values := in.QueryParams["active"]; bind.NoValue(values) {
+		// optional
+	} else if err := bind.Bool(bind.Alloc(&req.Query.Active), values[0]); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("query.active", values, err))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindContextReq binds ContextReq without reflection
+func ezapiBindContextReq(in bind.Input) (ContextReq, error) {
+	var req ContextReq
+	var fieldErrs []ezapi.FieldError
+
+	// context
+	if value := in.ContextValues["userId"]; value == nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("context.userId", nil, ezapi.ErrMissingContextValue))
+	} else if !bind.Value(&req.Context.UserID, value) {
+		fieldErrs = append(fieldErrs, ezapiFieldError("context.userId", nil, ezapi.ErrTypeMismatch))
+	}
+	if value := in.ContextValues["count"]; value == nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("context.count", nil, ezapi.ErrMissingContextValue))
+	} else if s, ok := value.(string); ok && !bind.Value(&req.Context.Count, value) {
+		if err := bind.Int(&req.Context.Count, s); err != nil {
+			fieldErrs = append(fieldErrs, ezapiFieldError("context.count", s, err))
+		}
+	} else if !bind.Value(&req.Context.Count, value) {
+		fieldErrs = append(fieldErrs, ezapiFieldError("context.count", nil, ezapi.ErrTypeMismatch))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindHeaderReq binds HeaderReq without reflection
+func ezapiBindHeaderReq(in bind.Input) (HeaderReq, error) {
+	var req HeaderReq
+	var fieldErrs []ezapi.FieldError
+
+	// header
+	if values := in.Header.Values("X-Request-Id"); len(values) == 0 {
+		fieldErrs = append(fieldErrs, ezapiFieldError("header.X-Request-Id", nil, ezapi.ErrMissingHeader))
+	} else if err := bind.String(&req.Header.RequestID, values[0]); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("header.X-Request-Id", values, err))
+	} else if err := bind.CheckFormat("uuid", string(req.Header.RequestID)); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("header.X-Request-Id", req.Header.RequestID, err))
+	}
+	if values := in.Header.Values("Accept-Language"); bind.NoValue(values) {
+		// optional
+	} else if err := bind.Slice(&req.Header.Languages, bind.SplitHeaderValues(values), bind.String); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("header.Accept-Language", values, err))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindCookieReq binds CookieReq without reflection
+func ezapiBindCookieReq(in bind.Input) (CookieReq, error) {
+	var req CookieReq
+	var fieldErrs []ezapi.FieldError
+
+	// cookie
+	if cookie := bind.FindCookie(in.Cookies, "session"); cookie == nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("cookie.session", nil, ezapi.ErrMissingCookie))
+	} else if err := bind.String(&req.Cookie.Session, cookie.Value); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("cookie.session", cookie.Value, err))
+	}
+	if cookie := bind.FindCookie(in.Cookies, "theme"); cookie == nil {
+		_ = bind.String(&req.Cookie.Theme, "light") // the default is checked when the handler is registered
+	} else if err := bind.String(&req.Cookie.Theme, cookie.Value); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("cookie.theme", cookie.Value, err))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindFormReq binds FormReq without reflection
+func ezapiBindFormReq(in bind.Input) (FormReq, error) {
+	var req FormReq
+	var fieldErrs []ezapi.FieldError
+
+	// form
+	if values := in.Form["name"]; len(values) == 0 {
+		fieldErrs = append(fieldErrs, ezapiFieldError("form.name", nil, ezapi.ErrMissingFormField))
+	} else if err := bind.String(&req.Form.Name, values[0]); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("form.name", values, err))
+	} else if err := bind.CheckMinLen(utf8.RuneCountInString(string(req.Form.Name)), 1); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("form.name", req.Form.Name, err))
+	}
+	if values := in.Form["age"]; len(values) == 0 {
+		fieldErrs = append(fieldErrs, ezapiFieldError("form.age", nil, ezapi.ErrMissingFormField))
+	} else if err := bind.Int(&req.Form.Age, values[0]); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("form.age", values, err))
+	} else if err := bind.CheckMin(float64(req.Form.Age), 0); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("form.age", req.Form.Age, err))
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindMultipartReq binds MultipartReq without reflection
+func ezapiBindMultipartReq(in bind.Input) (MultipartReq, error) {
+	var req MultipartReq
+	var fieldErrs []ezapi.FieldError
+
+	// multipart
+	if values := bind.FormValues(in.MultipartForm, "title"); len(values) == 0 {
+		fieldErrs = append(fieldErrs, ezapiFieldError("multipart.title", nil, ezapi.ErrMissingFormField))
+	} else if err := bind.String(&req.Multipart.Title, values[0]); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("multipart.title", values, err))
+	}
+	if files := bind.FormFiles(in.MultipartForm, "file"); len(files) == 0 {
+		fieldErrs = append(fieldErrs, ezapiFieldError("multipart.file", nil, ezapi.ErrMissingFormField))
+	} else {
+		req.Multipart.File = files[0]
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindJSONBodyReq binds JSONBodyReq without reflection
+func ezapiBindJSONBodyReq(in bind.Input) (JSONBodyReq, error) {
+	var req JSONBodyReq
+	var fieldErrs []ezapi.FieldError
+
+	// jsonBody
+	if err := (ezapi.JSONCodec{}).Decode(in.Body, &req.JSONBody); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("body", nil, err))
+	} else {
+		if err := bind.CheckMinLen(utf8.RuneCountInString(string(req.JSONBody.Title)), 1); err != nil {
+			fieldErrs = append(fieldErrs, ezapiFieldError("body.title", req.JSONBody.Title, err))
+		}
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// ezapiBindBodyReq binds BodyReq without reflection
+func ezapiBindBodyReq(in bind.Input) (BodyReq, error) {
+	var req BodyReq
+	var fieldErrs []ezapi.FieldError
+
+	// body
+	if in.BodyCodec == nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("body", nil, ezapi.ErrUnsupportedMediaType))
+	} else if err := in.BodyCodec.Decode(in.Body, &req.Body); err != nil {
+		fieldErrs = append(fieldErrs, ezapiFieldError("body", nil, err))
+	} else {
+		if err := bind.CheckMinLen(utf8.RuneCountInString(string(req.Body.Title)), 1); err != nil {
+			fieldErrs = append(fieldErrs, ezapiFieldError("body.title", req.Body.Title, err))
+		}
+	}
+
+	if len(fieldErrs) > 0 {
+		return req, ezapi.ValidationErrors{Errors: fieldErrs}
+	}
+	return req, nil
+}
+
+// the binding failure of the field at the location
+func ezapiFieldError(location string, value any, err error) ezapi.FieldError {
+	return ezapi.FieldError{Location: location, Reason: err.Error(), Value: value, Err: err}
+}
@@ -0,0 +1,72 @@
+// Package bindtest holds the request structs of the binder parity tests.
+// Their binders are generated by ezapi gen
+package bindtest
+
+//go:generate go run github.com/ic-it/ezapi/cmd/ezapi gen
+
+import (
+	"mime/multipart"
+)
+
+type PathReq struct {
+	Path struct {
+		ID   int    `ezapi:"id,min=1"`
+		Slug string `ezapi:"slug,minLen=1"`
+	} `ezapi:"path"`
+}
+
+type QueryReq struct {
+	Query struct {
+		Limit  int      `ezapi:"limit,default=20,max=100"`
+		Tags   []string `ezapi:"tag,optional"`
+		Active *bool    `ezapi:"active,optional"`
+	} `ezapi:"query"`
+}
+
+type ContextReq struct {
+	Context struct {
+		UserID string `ezapi:"userId"`
+		Count  int    `ezapi:"count"`
+	} `ezapi:"context"`
+}
+
+type HeaderReq struct {
+	Header struct {
+		RequestID string   `ezapi:"X-Request-Id,format=uuid"`
+		Languages []string `ezapi:"Accept-Language,optional"`
+	} `ezapi:"header"`
+}
+
+type CookieReq struct {
+	Cookie struct {
+		Session string `ezapi:"session"`
+		Theme   string `ezapi:"theme,default=light"`
+	} `ezapi:"cookie"`
+}
+
+type FormReq struct {
+	Form struct {
+		Name string `ezapi:"name,minLen=1"`
+		Age  int    `ezapi:"age,min=0"`
+	} `ezapi:"form"`
+}
+
+type MultipartReq struct {
+	Multipart struct {
+		Title string                `ezapi:"title"`
+		File  *multipart.FileHeader `ezapi:"file"`
+	} `ezapi:"multipart"`
+}
+
+type Todo struct {
+	Title string `json:"title" ezapi:"minLen=1"`
+	Done  bool   `json:"done"`
+}
+
+type JSONBodyReq struct {
+	JSONBody Todo `ezapi:"jsonBody"`
+}
+
+type BodyReq struct {
+	Body Todo `ezapi:"body"`
+}
@@ -0,0 +1,708 @@
+package ezapi
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	_OPENAPI_VERSION = "3.1.0"
+
+	// path to the component schemas
+	_OPENAPI_SCHEMA_REF_PREFIX = "#/components/schemas/"
+)
+
+// OpenAPISpec collects the handlers built with H and renders them
+// as an OpenAPI 3.1 document
+type OpenAPISpec struct {
+	mu sync.Mutex
+
+	title   string
+	version string
+
+	routes []Route
+}
+
+func NewOpenAPISpec(title, version string) *OpenAPISpec {
+	return &OpenAPISpec{
+		title:   title,
+		version: version,
+	}
+}
+
+// Document registers the handler in the spec under the given method and
+// pattern. The pattern uses the http.ServeMux syntax (e.g. "/todo/{id}")
+func Document(spec *OpenAPISpec, method, pattern string) HandlerOpt {
+	return onRoute(func(rt Route) {
+		rt.Method = method
+		rt.Pattern = pattern
+		spec.add(rt)
+	})
+}
+
+func (s *OpenAPISpec) add(rt Route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append(s.routes, rt)
+}
+
+// JSON renders the spec as an OpenAPI JSON document
+func (s *OpenAPISpec) JSON() ([]byte, error) {
+	return json.MarshalIndent(s.build(), "", "  ")
+}
+
+// YAML renders the spec as an OpenAPI YAML document
+func (s *OpenAPISpec) YAML() ([]byte, error) {
+	data, err := json.Marshal(s.build())
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(data)
+}
+
+// Handler serves the spec as JSON
+func (s *OpenAPISpec) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := s.JSON()
+		if err != nil {
+			DefaultInternalError{Err: err}.Render(ezapiContext[any]{r: r, w: w})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
+
+// OpenAPI document model
+type openAPIDoc struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       openAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas,omitempty"`
+}
+
+type openAPIOperation struct {
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 any                       `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+
+	// Constraints
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	MinItems  *int     `json:"minItems,omitempty"`
+	MaxItems  *int     `json:"maxItems,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	Enum      []any    `json:"enum,omitempty"`
+
+	Default any `json:"default,omitempty"`
+}
+
+// build the document from the registered operations
+func (s *OpenAPISpec) build() openAPIDoc {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schemas := newSchemaRegistry()
+	doc := openAPIDoc{
+		OpenAPI: _OPENAPI_VERSION,
+		Info: openAPIInfo{
+			Title:   s.title,
+			Version: s.version,
+		},
+		Paths: map[string]map[string]openAPIOperation{},
+	}
+
+	for _, rt := range s.routes {
+		path := openAPIPath(rt.Pattern)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]openAPIOperation{}
+		}
+		doc.Paths[path][strings.ToLower(rt.Method)] = buildOperation(rt, schemas)
+	}
+
+	doc.Components.Schemas = schemas.schemas
+	return doc
+}
+
+func buildOperation(op Route, schemas *schemaRegistry) openAPIOperation {
+	operation := openAPIOperation{
+		Responses: map[string]openAPIResponse{},
+	}
+
+	for _, p := range op.req.pathParams {
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:        p.alias,
+			In:          "path",
+			Description: p.description,
+			Required:    true, // path params are always required in OpenAPI
+			Schema:      paramSchema(p, schemas),
+		})
+	}
+	for _, p := range op.req.queryParams {
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:        p.alias,
+			In:          "query",
+			Description: p.description,
+			Required:    !p.optional,
+			Schema:      paramSchema(p, schemas),
+		})
+	}
+
+	for _, p := range op.req.headers {
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:        http.CanonicalHeaderKey(p.alias),
+			In:          "header",
+			Description: p.description,
+			Required:    !p.optional,
+			Schema:      paramSchema(p, schemas),
+		})
+	}
+
+	for _, p := range op.req.cookies {
+		schema := &openAPISchema{Type: "string"}
+		if p.typ != cookieType && p.typ != cookiePtrType {
+			schema = paramSchema(p, schemas)
+		}
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:        p.alias,
+			In:          "cookie",
+			Description: p.description,
+			Required:    !p.optional,
+			Schema:      schema,
+		})
+	}
+
+	if op.req.hasJSONBody() {
+		operation.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content: map[string]openAPIMediaType{
+				"application/json": {Schema: schemas.schemaFor(op.req.jsonBodyType)},
+			},
+		}
+	}
+
+	if op.req.hasBody() {
+		content := map[string]openAPIMediaType{}
+		for _, codec := range codecsForType(op.codecs, op.req.bodyType) {
+			for _, mediaType := range codec.MediaTypes() {
+				if _, ok := content[mediaType]; !ok {
+					content[mediaType] = openAPIMediaType{Schema: schemas.schemaFor(op.req.bodyType)}
+				}
+			}
+		}
+		operation.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content:  content,
+		}
+	}
+
+	if op.req.hasForm() {
+		operation.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content: map[string]openAPIMediaType{
+				"application/x-www-form-urlencoded": {Schema: formSchema(op.req.form, schemas)},
+			},
+		}
+	}
+
+	if op.req.hasMultipart() {
+		operation.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content: map[string]openAPIMediaType{
+				"multipart/form-data": {Schema: formSchema(op.req.multipart, schemas)},
+			},
+		}
+	}
+
+	// success response
+	okResp := openAPIResponse{Description: "OK"}
+	switch {
+	case op.respType.Kind() == reflect.String:
+		okResp.Content = map[string]openAPIMediaType{
+			"text/plain": {Schema: &openAPISchema{Type: "string"}},
+		}
+	case op.respType.Implements(reflect.TypeOf((*Renderable)(nil)).Elem()):
+		// custom rendering, the body is unknown
+	default:
+		okResp.Content = map[string]openAPIMediaType{
+			op.contentType: {Schema: schemas.schemaFor(op.respType)},
+		}
+		for _, codec := range codecsForType(op.codecs, op.respType) {
+			for _, mediaType := range codec.MediaTypes() {
+				if _, ok := okResp.Content[mediaType]; !ok {
+					okResp.Content[mediaType] = openAPIMediaType{Schema: schemas.schemaFor(op.respType)}
+				}
+			}
+		}
+	}
+	status := op.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	okResp.Description = http.StatusText(status)
+	if bodylessStatus(status) {
+		okResp.Content = nil
+	}
+	operation.Responses[strconv.Itoa(status)] = okResp
+
+	for _, e := range op.errorResponses() {
+		mediaType := "application/json"
+		switch {
+		case e.typ == problemType:
+			mediaType = _PROBLEM_CONTENT_TYPE
+		case e.typ.Kind() == reflect.String:
+			mediaType = "text/plain"
+		}
+		operation.Responses[strconv.Itoa(e.status)] = openAPIResponse{
+			Description: http.StatusText(e.status),
+			Content: map[string]openAPIMediaType{
+				mediaType: {Schema: schemas.schemaFor(e.typ)},
+			},
+		}
+	}
+
+	return operation
+}
+
+// add the constraints to the schema, the item constraints go to the items of arrays
+func constrainedSchema(schema *openAPISchema, c *fieldConstraints) *openAPISchema {
+	if c == nil {
+		return schema
+	}
+	target := schema
+	if schema.Type == "array" && schema.Items != nil {
+		schema.MinItems = c.minLen
+		schema.MaxItems = c.maxLen
+		target = schema.Items
+	} else {
+		target.MinLength = c.minLen
+		target.MaxLength = c.maxLen
+	}
+	target.Minimum = c.min
+	target.Maximum = c.max
+	if c.pattern != nil {
+		target.Pattern = c.pattern.String()
+	}
+	if c.format != "" {
+		target.Format = c.format
+	}
+	for _, e := range c.enum {
+		if target.Type == "integer" || target.Type == "number" {
+			if n, err := strconv.ParseFloat(e, 64); err == nil {
+				target.Enum = append(target.Enum, n)
+				continue
+			}
+		}
+		target.Enum = append(target.Enum, e)
+	}
+	return schema
+}
+
+// schema of the param with its constraints and default value
+func paramSchema(p reflectedKeyVal, schemas *schemaRegistry) *openAPISchema {
+	schema := constrainedSchema(schemas.schemaFor(p.typ), p.constraints)
+	if p.hasDefault() {
+		schema.Default = schemaDefault(p.defaultValue)
+	}
+	return schema
+}
+
+// the default value in the form of its schema, e.g. the durations are strings
+func schemaDefault(v reflect.Value) any {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem() == reflect.TypeOf(time.Duration(0)) {
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = time.Duration(v.Index(i).Int()).String()
+		}
+		return items
+	}
+	return v.Interface()
+}
+
+// build the object schema of the form fields
+func formSchema(params []reflectedKeyVal, schemas *schemaRegistry) *openAPISchema {
+	schema := &openAPISchema{
+		Type:       "object",
+		Properties: map[string]*openAPISchema{},
+	}
+	for _, p := range params {
+		var fieldSchema *openAPISchema
+		switch p.typ {
+		case fileHeaderType:
+			fieldSchema = &openAPISchema{Type: "string", Format: "binary"}
+		case fileHeadersType:
+			fieldSchema = &openAPISchema{Type: "array", Items: &openAPISchema{Type: "string", Format: "binary"}}
+		default:
+			fieldSchema = paramSchema(p, schemas)
+		}
+		fieldSchema.Description = p.description
+		schema.Properties[p.alias] = fieldSchema
+		if !p.optional {
+			schema.Required = append(schema.Required, p.alias)
+		}
+	}
+	return schema
+}
+
+// convert http.ServeMux pattern to the OpenAPI path
+func openAPIPath(pattern string) string {
+	// strip the method and the host
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+		pattern = strings.TrimSpace(pattern[i:])
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	pattern = strings.ReplaceAll(pattern, "{$}", "")
+	pattern = strings.ReplaceAll(pattern, "...}", "}")
+	return pattern
+}
+
+// schema registry keeps named struct schemas in the components section
+type schemaRegistry struct {
+	schemas map[string]*openAPISchema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: map[string]*openAPISchema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// build the schema for the given type
+func (sr *schemaRegistry) schemaFor(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// well known types
+	switch t {
+	case problemType:
+		return &openAPISchema{Ref: _OPENAPI_SCHEMA_REF_PREFIX + sr.registerProblem()}
+	case reflect.TypeOf(uuid.UUID{}):
+		return &openAPISchema{Type: "string", Format: "uuid"}
+	case reflect.TypeOf(time.Time{}):
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case reflect.TypeOf(time.Duration(0)):
+		return &openAPISchema{Type: "string", Format: "duration"}
+	}
+	if t.Kind() != reflect.String && t.Kind() != reflect.Struct &&
+		(t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		return &openAPISchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Uint8, reflect.Uint16:
+		return &openAPISchema{Type: "integer", Format: "int32", Minimum: new(float64)}
+	case reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int64", Minimum: new(float64)}
+	case reflect.Uint, reflect.Uint64:
+		// beyond int64, no format fits
+		return &openAPISchema{Type: "integer", Minimum: new(float64)}
+	case reflect.Float32:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: sr.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: sr.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+			return &openAPISchema{Type: "string"}
+		}
+		if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+			// custom json, the shape is unknown
+			return &openAPISchema{}
+		}
+		if t.Name() == "" {
+			return sr.structSchema(t)
+		}
+		return &openAPISchema{Ref: _OPENAPI_SCHEMA_REF_PREFIX + sr.register(t)}
+	default:
+		// interfaces, funcs etc. can be anything
+		return &openAPISchema{}
+	}
+}
+
+// register named struct in the components section
+func (sr *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := sr.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	// avoid collisions between types with the same name from different packages
+	for i := 2; sr.schemas[name] != nil; i++ {
+		name = t.Name() + strconv.Itoa(i)
+	}
+	sr.names[t] = name
+	sr.schemas[name] = &openAPISchema{} // placeholder for recursive types
+	*sr.schemas[name] = *sr.structSchema(t)
+	return name
+}
+
+// register the problem document, its extensions are free-form
+func (sr *schemaRegistry) registerProblem() string {
+	if name, ok := sr.names[problemType]; ok {
+		return name
+	}
+	name := "Problem"
+	for i := 2; sr.schemas[name] != nil; i++ {
+		name = "Problem" + strconv.Itoa(i)
+	}
+	schema := sr.structSchema(reflect.TypeOf(problemMembers{}))
+	schema.AdditionalProperties = &openAPISchema{}
+	sr.names[problemType] = name
+	sr.schemas[name] = schema
+	return name
+}
+
+// build the object schema of the struct, respecting the json tags
+func (sr *schemaRegistry) structSchema(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{
+		Type:       "object",
+		Properties: map[string]*openAPISchema{},
+	}
+	sr.collectFields(t, schema)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (sr *schemaRegistry) collectFields(t reflect.Type, schema *openAPISchema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// embedded structs without a name are flattened like encoding/json does
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				sr.collectFields(ft, schema)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fieldSchema := sr.schemaFor(field.Type)
+		if tag := field.Tag.Get(_EZAPI_TAG_NAME); tag != "" {
+			// the tag errors are reported by ReflectReq
+			constraints, description, _ := parseConstraints(tag)
+			fieldSchema = constrainedSchema(fieldSchema, constraints)
+			fieldSchema.Description = description
+		}
+		schema.Properties[name] = fieldSchema
+
+		omitempty := false
+		for _, opt := range strings.Split(opts, ",") {
+			if opt == "omitempty" || opt == "omitzero" {
+				omitempty = true
+			}
+		}
+		if !omitempty && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// helper function to convert json document to yaml, keeping the key order
+func jsonToYAML(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	var err error
+	if v, err = decodeOrdered(dec); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writeYAML(&buf, v, 0)
+	return buf.Bytes(), nil
+}
+
+// json object with the preserved key order
+type orderedObject struct {
+	keys   []string
+	values []any
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := orderedObject{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj.keys = append(obj.keys, key.(string))
+			obj.values = append(obj.values, value)
+		}
+		_, err = dec.Token() // closing '}'
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err = dec.Token() // closing ']'
+		return arr, err
+	default:
+		return tok, nil
+	}
+}
+
+func writeYAML(buf *bytes.Buffer, v any, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch v := v.(type) {
+	case orderedObject:
+		if len(v.keys) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		if indent > 0 {
+			buf.WriteString("\n")
+		}
+		for i, key := range v.keys {
+			buf.WriteString(pad + yamlScalar(key) + ":")
+			writeYAML(buf, v.values[i], indent+1)
+		}
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteString("\n")
+		for _, item := range v {
+			buf.WriteString(pad + "-")
+			writeYAML(buf, item, indent+1)
+		}
+	default:
+		buf.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+func yamlScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		// quote everything that could be read as something other than a plain string
+		if v == "" || strings.ContainsAny(v, ":#{}[],&*!|>'\"%@`\n\t-?") ||
+			strings.TrimSpace(v) != v || v == "true" || v == "false" || v == "null" || v == "~" {
+			quoted, _ := json.Marshal(v)
+			return string(quoted)
+		}
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return strconv.Quote(v)
+		}
+		return v
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,102 @@
+package ezapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchemaForIntegers(t *testing.T) {
+	sr := newSchemaRegistry()
+	for value, want := range map[any]struct {
+		format   string
+		unsigned bool
+	}{
+		int(0):    {format: "int64"},
+		int64(0):  {format: "int64"},
+		int32(0):  {format: "int32"},
+		uint16(0): {format: "int32", unsigned: true},
+		uint32(0): {format: "int64", unsigned: true},
+		uint(0):   {unsigned: true},
+		uint64(0): {unsigned: true},
+	} {
+		schema := sr.schemaFor(reflect.TypeOf(value))
+		if schema.Type != "integer" || schema.Format != want.format {
+			t.Errorf("%T: expected integer/%s, got %s/%s", value, want.format, schema.Type, schema.Format)
+		}
+		if unsigned := schema.Minimum != nil && *schema.Minimum == 0; unsigned != want.unsigned {
+			t.Errorf("%T: expected the minimum 0 only for the unsigned types, got %v", value, schema.Minimum)
+		}
+	}
+}
+
+func TestDurationDefault(t *testing.T) {
+	type req struct {
+		Query struct {
+			Timeout time.Duration `ezapi:"timeout,default=1m30s"`
+		} `ezapi:"query"`
+	}
+	r := NewRouter()
+	GET(r, "/wait", func(Context[req]) (string, RespError) { return "", nil })
+
+	params := r.OpenAPI("test", "1").build().Paths["/wait"]["get"].Parameters
+	if len(params) != 1 {
+		t.Fatalf("expected the timeout param, got %v", params)
+	}
+	if schema := params[0].Schema; schema.Format != "duration" || schema.Default != "1m30s" {
+		t.Errorf("expected the default as a duration string, got %s %v", schema.Format, schema.Default)
+	}
+}
+
+func TestSuccessStatusDocumented(t *testing.T) {
+	r := NewRouter()
+	POST(r, "/todo", func(Context[struct{}]) (Response[string], RespError) {
+		return Created("/todo/1", "1"), nil
+	}, SuccessStatus(http.StatusCreated))
+	DELETE(r, "/todo/{id}", func(Context[struct{}]) (Response[struct{}], RespError) {
+		return NoContent(), nil
+	})
+
+	paths := r.OpenAPI("test", "1").build().Paths
+	if _, ok := paths["/todo"]["post"].Responses["201"]; !ok {
+		t.Errorf("expected the 201 of the create route, got %v", paths["/todo"]["post"].Responses)
+	}
+	if _, ok := paths["/todo/{id}"]["delete"].Responses["204"]; !ok {
+		t.Errorf("expected the 204 of the delete route, got %v", paths["/todo/{id}"]["delete"].Responses)
+	}
+}
+
+func TestMapResponseContent(t *testing.T) {
+	r := NewRouter(Codecs(XMLCodec{}))
+	GET(r, "/counts", func(Context[struct{}]) (map[string]int, RespError) { return nil, nil })
+
+	content := r.OpenAPI("test", "1").build().Paths["/counts"]["get"].Responses["200"].Content
+	if _, ok := content["application/json"]; !ok {
+		t.Errorf("expected the json content, got %v", content)
+	}
+	if _, ok := content["application/xml"]; ok {
+		t.Error("expected no xml content for the map response")
+	}
+}
+
+func TestCookieParameters(t *testing.T) {
+	type req struct {
+		Cookies struct {
+			Session *http.Cookie `ezapi:"session"`
+			Visits  int          `ezapi:"visits,optional"`
+		} `ezapi:"cookie"`
+	}
+	r := NewRouter()
+	GET(r, "/me", func(Context[req]) (string, RespError) { return "", nil })
+
+	params := r.OpenAPI("test", "1").build().Paths["/me"]["get"].Parameters
+	if len(params) != 2 {
+		t.Fatalf("expected the cookie params, got %v", params)
+	}
+	for i, name := range []string{"session", "visits"} {
+		if params[i].Name != name || params[i].In != "cookie" || params[i].Required != (name == "session") {
+			t.Errorf("expected the %s cookie param, got %+v", name, params[i])
+		}
+	}
+}
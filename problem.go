@@ -0,0 +1,204 @@
+package ezapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+const _PROBLEM_CONTENT_TYPE = "application/problem+json"
+
+var problemType = reflect.TypeOf(Problem{})
+
+// Problem is an RFC 9457 problem details error, rendered as application/problem+json
+type Problem struct {
+	// URI reference of the problem type, "about:blank" if empty
+	Type string
+	// Short summary of the problem type, the status text if empty
+	Title string
+	// Status code of the response, 500 if not set
+	Status int
+	// Explanation of this occurrence of the problem
+	Detail string
+	// URI reference of this occurrence of the problem
+	Instance string
+	// Extension members, rendered next to the standard ones
+	Extensions map[string]any
+
+	// The cause, not rendered
+	Err error
+}
+
+// NewProblem creates the problem of the status with the detail
+func NewProblem(status int, detail string) Problem {
+	return Problem{Status: status, Detail: detail}
+}
+
+// ValidationProblem converts binding and validation failures to a problem
+// with the failed fields in the "errors" extension
+func ValidationProblem(verrs ValidationErrors) Problem {
+	status := verrs.Status
+	if status == 0 {
+		status = http.StatusBadRequest
+	}
+	return Problem{
+		Status:     status,
+		Detail:     "Invalid request",
+		Extensions: map[string]any{"errors": verrs.Errors},
+		Err:        verrs,
+	}
+}
+
+// ProblemFromError converts the error to a problem of the status.
+// Problems are returned as is, ValidationErrors become a ValidationProblem
+// with the status if they don't set their own and an OpaqueError only
+// renders its correlation ID
+func ProblemFromError(status int, err error) Problem {
+	var problem Problem
+	if errors.As(err, &problem) {
+		return problem
+	}
+	var opaque OpaqueError
+	if errors.As(err, &opaque) {
+		return Problem{
+			Status:     status,
+			Extensions: map[string]any{"correlationId": opaque.ID},
+			Err:        err,
+		}
+	}
+	var verrs ValidationErrors
+	if errors.As(err, &verrs) {
+		if verrs.Status == 0 {
+			verrs.Status = status
+		}
+		problem = ValidationProblem(verrs)
+		problem.Err = err
+		return problem
+	}
+	return Problem{Status: status, Detail: err.Error(), Err: err}
+}
+
+// WithExtension returns a copy of the problem with the extension member set
+func (p Problem) WithExtension(key string, value any) Problem {
+	extensions := make(map[string]any, len(p.Extensions)+1)
+	for k, v := range p.Extensions {
+		extensions[k] = v
+	}
+	extensions[key] = value
+	p.Extensions = extensions
+	return p
+}
+
+func (p Problem) Error() string {
+	title := p.title()
+	if p.Detail == "" {
+		return title
+	}
+	return title + ": " + p.Detail
+}
+
+func (p Problem) Unwrap() error {
+	return p.Err
+}
+
+func (p Problem) Render(ctx BaseContext) error {
+	return writeJSON(ctx.GetW(), p.status(), _PROBLEM_CONTENT_TYPE, p)
+}
+
+func (p Problem) status() int {
+	if p.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return p.Status
+}
+
+func (p Problem) title() string {
+	if p.Title == "" {
+		return http.StatusText(p.status())
+	}
+	return p.Title
+}
+
+// standard members of the problem document
+type problemMembers struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+var problemMemberNames = []string{"type", "title", "status", "detail", "instance"}
+
+// MarshalJSON flattens the extensions into the problem document,
+// they can't replace the standard members
+func (p Problem) MarshalJSON() ([]byte, error) {
+	doc := make(map[string]any, len(p.Extensions)+len(problemMemberNames))
+	for k, v := range p.Extensions {
+		doc[k] = v
+	}
+	for _, name := range problemMemberNames {
+		delete(doc, name)
+	}
+	doc["title"] = p.title()
+	doc["status"] = p.status()
+	if p.Type != "" {
+		doc["type"] = p.Type
+	}
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON reads the problem document, e.g. with ErrorBody[Problem]
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var members problemMembers
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	var extensions map[string]any
+	if err := json.Unmarshal(data, &extensions); err != nil {
+		return err
+	}
+	for _, name := range problemMemberNames {
+		delete(extensions, name)
+	}
+	if len(extensions) == 0 {
+		extensions = nil
+	}
+	*p = Problem{
+		Type:       members.Type,
+		Title:      members.Title,
+		Status:     members.Status,
+		Detail:     members.Detail,
+		Instance:   members.Instance,
+		Extensions: extensions,
+	}
+	return nil
+}
+
+// ProblemErrors makes the default errors of the handler render as problems:
+// the unmarshal, unsupported media type, not acceptable, validation and
+// internal errors
+func ProblemErrors() HandlerOpt {
+	return func(o *handlerOpts) {
+		o.problems = true
+		o.defaultUnmarshalErrorConstructor = func(err error) RespError {
+			return ProblemFromError(http.StatusBadRequest, err)
+		}
+		o.unsupportedMediaTypeErrorConstructor = func(contentType string) RespError {
+			return NewProblem(http.StatusUnsupportedMediaType, DefaultUnsupportedMediaTypeError{ContentType: contentType}.Error())
+		}
+		o.notAcceptableErrorConstructor = func(accept string) RespError {
+			return NewProblem(http.StatusNotAcceptable, DefaultNotAcceptableError{Accept: accept}.Error())
+		}
+		o.internalErrorConstructor = func(err error) RespError {
+			return ProblemFromError(http.StatusInternalServerError, err)
+		}
+	}
+}
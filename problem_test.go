@@ -0,0 +1,132 @@
+package ezapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// decode the problem document of the response
+func problemBody(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	if contentType := w.Header().Get("Content-Type"); contentType != _PROBLEM_CONTENT_TYPE {
+		t.Fatalf("expected %s, got %q: %s", _PROBLEM_CONTENT_TYPE, contentType, w.Body)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestProblemRender(t *testing.T) {
+	handler := H(func(Context[struct{}]) (string, RespError) {
+		problem := NewProblem(http.StatusConflict, "name is taken").
+			WithExtension("name", "ada").
+			WithExtension("status", 200)
+		problem.Type = "https://example.com/problems/taken"
+		problem.Instance = "/users/ada"
+		return "", problem
+	})
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", w.Code, w.Body)
+	}
+	doc := problemBody(t, w)
+	for member, value := range map[string]any{
+		"type":     "https://example.com/problems/taken",
+		"title":    "Conflict",
+		"status":   float64(http.StatusConflict), // the extension can't replace it
+		"detail":   "name is taken",
+		"instance": "/users/ada",
+		"name":     "ada",
+	} {
+		if doc[member] != value {
+			t.Errorf("expected %s %v, got %v", member, value, doc[member])
+		}
+	}
+
+	var decoded Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Status != http.StatusConflict || decoded.Extensions["name"] != "ada" {
+		t.Errorf("expected the problem to round trip, got %+v", decoded)
+	}
+}
+
+type problemReq struct {
+	Query struct {
+		Limit int `ezapi:"limit,max=100"`
+	} `ezapi:"query"`
+	Body struct {
+		Title string `json:"title"`
+	} `ezapi:"body"`
+}
+
+func TestProblemErrors(t *testing.T) {
+	handler := H(func(Context[problemReq]) (map[string]int, RespError) {
+		return map[string]int{}, nil
+	}, ProblemErrors())
+
+	for _, tc := range []struct {
+		name        string
+		target      string
+		contentType string
+		accept      string
+		status      int
+		detail      string
+		errors      []string
+	}{
+		{"binding", "/?limit=x", "application/json", "", http.StatusBadRequest, "Invalid request", []string{"query.limit"}},
+		{"constraint", "/?limit=500", "application/json", "", http.StatusBadRequest, "Invalid request", []string{"query.limit"}},
+		{"media type", "/?limit=1", "text/plain", "", http.StatusUnsupportedMediaType, "unsupported media type: text/plain", nil},
+		{"accept", "/?limit=1", "application/json", "text/csv", http.StatusNotAcceptable, "not acceptable: text/csv", nil},
+	} {
+		r := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(`{"title":"a"}`))
+		r.Header.Set("Content-Type", tc.contentType)
+		if tc.accept != "" {
+			r.Header.Set("Accept", tc.accept)
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+
+		if w.Code != tc.status {
+			t.Errorf("%s: expected %d, got %d: %s", tc.name, tc.status, w.Code, w.Body)
+			continue
+		}
+		doc := problemBody(t, w)
+		if doc["status"] != float64(tc.status) || doc["detail"] != tc.detail {
+			t.Errorf("%s: expected the %d problem %q, got %v", tc.name, tc.status, tc.detail, doc)
+		}
+		fieldErrs, _ := doc["errors"].([]any)
+		if len(fieldErrs) != len(tc.errors) {
+			t.Errorf("%s: expected the field errors %q, got %v", tc.name, tc.errors, doc["errors"])
+			continue
+		}
+		for i, location := range tc.errors {
+			if fieldErr, _ := fieldErrs[i].(map[string]any); fieldErr["location"] != location {
+				t.Errorf("%s: expected the failure of %s, got %v", tc.name, location, fieldErrs[i])
+			}
+		}
+	}
+}
+
+func TestValidationProblem(t *testing.T) {
+	handler := H(func(Context[validatedReq]) (string, RespError) { return "ok", nil }, ProblemErrors())
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", w.Code, w.Body)
+	}
+	doc := problemBody(t, w)
+	fieldErrs, _ := doc["errors"].([]any)
+	if doc["status"] != float64(http.StatusUnprocessableEntity) || len(fieldErrs) != 1 {
+		t.Errorf("expected the 422 problem with the failed field, got %v", doc)
+	}
+}
@@ -0,0 +1,102 @@
+package ezapi
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+)
+
+// PanicError is the value of a panic recovered by H
+type PanicError struct {
+	Value any
+}
+
+func (e PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value if it is an error, e.g. panic(err)
+func (e PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// PanicReport is a panic recovered by H, delivered to the panic observer
+type PanicReport struct {
+	// Correlation ID, rendered to the client instead of the value
+	ID    string
+	Value any
+	// Stack of the panicking goroutine
+	Stack   []byte
+	Request *http.Request
+}
+
+// PanicErrorConstructor sets the error rendered for the recovered panics.
+// By default it is the internal error of an OpaqueError with the
+// correlation ID of the report, the panic value is never sent to the client
+func PanicErrorConstructor(constructor func(value any) RespError) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.panicErrorConstructor = constructor
+	}
+}
+
+// OnPanic sets the observer of the panics recovered by H, e.g. to log them.
+// Every panic is reported once: to the observer, to the ErrorReporter if
+// there is no observer, or to the standard logger if there is neither
+func OnPanic(observer func(PanicReport)) HandlerOpt {
+	return func(o *handlerOpts) {
+		o.panicObserver = observer
+	}
+}
+
+// recover the panic of the handler or of its middlewares, report it and
+// render it as an error. http.ErrAbortHandler is re-panicked to abort the
+// response as net/http expects
+func (o *handlerOpts) recoverPanic(ctx BaseContext) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	if err, ok := value.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+		panic(value)
+	}
+
+	report := PanicReport{
+		ID:      uuid.NewString(),
+		Value:   value,
+		Stack:   debug.Stack(),
+		Request: ctx.GetR(),
+	}
+	switch {
+	case o.panicObserver != nil:
+		o.panicObserver(report)
+	case o.errorReporter != nil:
+		o.errorReporter(ErrorReport{
+			ID:      report.ID,
+			Err:     PanicError{Value: value},
+			Stack:   report.Stack,
+			Request: report.Request,
+		})
+	default:
+		log.Printf("ezapi: panic serving %s %s (correlation id %s): %v\n%s",
+			report.Request.Method, report.Request.URL.Path, report.ID, value, report.Stack)
+	}
+
+	if isCommitted(ctx.GetW()) {
+		return
+	}
+	var respErr RespError
+	if o.panicErrorConstructor != nil {
+		respErr = o.panicErrorConstructor(value)
+	} else {
+		respErr = o.internalErrorConstructor(OpaqueError{ID: report.ID})
+	}
+	// rendered as is, the panic is already reported
+	if err := respErr.Render(ctx); err != nil {
+		o.renderInternalError(ctx, err, err)
+	}
+}
@@ -0,0 +1,103 @@
+package ezapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func panickingHandler(Context[struct{}]) (string, RespError) {
+	panic("boom secret")
+}
+
+// serve the request with the standard logger captured
+func servePanic(t *testing.T, handler http.HandlerFunc) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+	var logged bytes.Buffer
+	output := log.Writer()
+	log.SetOutput(&logged)
+	defer log.SetOutput(output)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w, logged.String()
+}
+
+func TestPanicBodyIsOpaque(t *testing.T) {
+	for name, opts := range map[string][]HandlerOpt{
+		"default":  nil,
+		"problems": {ProblemErrors()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w, logged := servePanic(t, H(panickingHandler, opts...))
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("expected 500, got %d: %s", w.Code, w.Body)
+			}
+			if strings.Contains(w.Body.String(), "boom") {
+				t.Errorf("expected the panic value to stay out of the body, got %s", w.Body)
+			}
+			var body struct {
+				CorrelationID string `json:"correlationId"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.CorrelationID == "" || !strings.Contains(logged, body.CorrelationID) {
+				t.Errorf("expected the correlation id %q in the log %q", body.CorrelationID, logged)
+			}
+			if strings.Count(logged, "boom secret") != 1 {
+				t.Errorf("expected the panic value to be logged once, got %q", logged)
+			}
+		})
+	}
+}
+
+func TestPanicReportedOnce(t *testing.T) {
+	var panics []PanicReport
+	var errs []ErrorReport
+	observer := OnPanic(func(report PanicReport) { panics = append(panics, report) })
+	reporter := ReportErrors(func(report ErrorReport) { errs = append(errs, report) })
+
+	_, logged := servePanic(t, H(panickingHandler, observer, reporter))
+	if len(panics) != 1 || len(errs) != 0 || logged != "" {
+		t.Errorf("expected a single panic report, got %d panic and %d error reports, log %q", len(panics), len(errs), logged)
+	}
+
+	panics, errs = nil, nil
+	w, logged := servePanic(t, H(panickingHandler, reporter))
+	if len(errs) != 1 || logged != "" {
+		t.Fatalf("expected a single error report, got %d, log %q", len(errs), logged)
+	}
+	var panicErr PanicError
+	if !errors.As(errs[0].Err, &panicErr) || panicErr.Value != "boom secret" {
+		t.Errorf("expected the panic error to be reported, got %v", errs[0].Err)
+	}
+	if !strings.Contains(w.Body.String(), errs[0].ID) {
+		t.Errorf("expected the report id %q in the body %s", errs[0].ID, w.Body)
+	}
+}
+
+func TestMiddlewarePanicRecovered(t *testing.T) {
+	var panics []PanicReport
+	panicking := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("middleware boom")
+		})
+	}
+	handler := H(func(Context[struct{}]) (string, RespError) { return "ok", nil },
+		Use(panicking),
+		OnPanic(func(report PanicReport) { panics = append(panics, report) }),
+	)
+
+	w, _ := servePanic(t, handler)
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d: %s", w.Code, w.Body)
+	}
+	if len(panics) != 1 || panics[0].Value != "middleware boom" {
+		t.Errorf("expected the middleware panic to be reported once, got %+v", panics)
+	}
+}
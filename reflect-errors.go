@@ -0,0 +1,80 @@
+package ezapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ReflectError is a misconfiguration of a single request struct field
+type ReflectError struct {
+	// Path of the Go field, e.g. "Query.Limit"
+	Field string
+	// The ezapi tag of the field
+	Tag string
+	// What is wrong, matches ErrInvalidTag, ErrorUnsuppType, etc. with errors.Is
+	Err error
+}
+
+func (e ReflectError) Error() string {
+	problem := strings.ReplaceAll(e.Err.Error(), "\n", ": ")
+	if e.Field == "" {
+		return problem
+	}
+	return fmt.Sprintf("%s `ezapi:\"%s\"`: %s", e.Field, e.Tag, problem)
+}
+
+func (e ReflectError) Unwrap() error {
+	return e.Err
+}
+
+// ReflectErrors carries every misconfiguration of the request struct
+type ReflectErrors struct {
+	// Name of the request struct
+	Type   string
+	Errors []ReflectError
+}
+
+func (e ReflectErrors) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("error reflecting request struct '%s': %s", e.Type, strings.Join(msgs, "; "))
+}
+
+func (e ReflectErrors) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// helper function to attach the errors of the field to the section they belong to
+func sectionReflectErrors(section reflect.StructField, errs []ReflectError) []ReflectError {
+	tag := section.Tag.Get(_EZAPI_TAG_NAME)
+	for i, err := range errs {
+		if err.Field == "" {
+			errs[i].Field = section.Name
+			errs[i].Tag = tag
+		} else {
+			errs[i].Field = section.Name + "." + err.Field
+		}
+	}
+	return errs
+}
+
+// print the errors the way ReflectReq always did before panicking
+func panicReflectErrors(err error) {
+	reflectErrs, ok := err.(ReflectErrors)
+	if !ok {
+		panic(err)
+	}
+	for i, err := range reflectErrs.Errors {
+		errStr := err.Error()
+		errStr = strings.ReplaceAll(errStr, "\n", "\n| ")
+		fmt.Printf("Error %d: %s\n\n", i+1, errStr)
+	}
+	panic(fmt.Sprintf("error reflecting request struct '%s'", reflectErrs.Type))
+}
@@ -13,15 +13,21 @@ const (
 
 	// tag values
 	_EZAPI_TAG_JSON_BODY    = "jsonBody"
+	_EZAPI_TAG_BODY         = "body"
 	_EZAPI_TAG_PATH_PARAMS  = "path"
 	_EZAPI_TAG_QUERY_PARAMS = "query"
 	_EZAPI_TAG_CONTEXT      = "context"
+	_EZAPI_TAG_HEADERS      = "header"
+	_EZAPI_TAG_COOKIES      = "cookie"
+	_EZAPI_TAG_FORM         = "form"
+	_EZAPI_TAG_MULTIPART    = "multipart"
 
 	// tag values for params
 	_EZAPI_TAG_OPTIONAL = "optional"
 	_EZAPI_TAG_REQUIRED = "required"
 	_EZAPI_TAG_ALIAS    = "alias"
 	_EZAPI_TAG_DESC     = "desc"
+	_EZAPI_TAG_DEFAULT  = "default"
 )
 
 // to this struct represents the reflected struct
@@ -34,6 +40,14 @@ type reflectedReq struct {
 	jsonBodyFieldName   string
 	jsonBodyValidatorCb func(any, BaseContext) RespError
 
+	// Fields of the json body or body with their constraints
+	bodyFields []reflectedKeyVal
+
+	// Body decoded by the codec matching the Content-Type
+	bodyType        reflect.Type
+	bodyFieldName   string
+	bodyValidatorCb func(any, BaseContext) RespError
+
 	// Path Params
 	pathParamsType        reflect.Type
 	pathParams            []reflectedKeyVal
@@ -51,12 +65,40 @@ type reflectedReq struct {
 	contextValues      []reflectedKeyVal
 	contextValuesName  string
 	contextValidatorCb func(any, BaseContext) RespError
+
+	// Headers
+	headersType        reflect.Type
+	headers            []reflectedKeyVal
+	headersFieldName   string
+	headersValidatorCb func(any, BaseContext) RespError
+
+	// Cookies
+	cookiesType        reflect.Type
+	cookies            []reflectedKeyVal
+	cookiesFieldName   string
+	cookiesValidatorCb func(any, BaseContext) RespError
+
+	// URL-encoded Form
+	formType        reflect.Type
+	form            []reflectedKeyVal
+	formFieldName   string
+	formValidatorCb func(any, BaseContext) RespError
+
+	// Multipart Form
+	multipartType        reflect.Type
+	multipart            []reflectedKeyVal
+	multipartFieldName   string
+	multipartValidatorCb func(any, BaseContext) RespError
 }
 
 func (rq reflectedReq) hasJSONBody() bool {
 	return rq.jsonBodyType != nil
 }
 
+func (rq reflectedReq) hasBody() bool {
+	return rq.bodyType != nil
+}
+
 func (rq reflectedReq) hasPathParams() bool {
 	return rq.pathParamsType != nil
 }
@@ -69,6 +111,44 @@ func (rq reflectedReq) hasContextValues() bool {
 	return rq.contextValuesType != nil
 }
 
+func (rq reflectedReq) hasHeaders() bool {
+	return rq.headersType != nil
+}
+
+func (rq reflectedReq) hasCookies() bool {
+	return rq.cookiesType != nil
+}
+
+func (rq reflectedReq) hasForm() bool {
+	return rq.formType != nil
+}
+
+func (rq reflectedReq) hasMultipart() bool {
+	return rq.multipartType != nil
+}
+
+// only one section can read the request body
+func (rq reflectedReq) readsBody() bool {
+	return rq.hasJSONBody() || rq.hasBody() || rq.hasForm() || rq.hasMultipart()
+}
+
+// reports whether the request or one of its sections is Validatable
+func (rq reflectedReq) validates() bool {
+	t := rq.typ
+	if t == nil {
+		return false
+	}
+	if rq.isPtr {
+		t = reflect.PointerTo(t)
+	}
+	return getIsValidatable(t) ||
+		rq.jsonBodyValidatorCb != nil || rq.bodyValidatorCb != nil ||
+		rq.pathParamsValidatorCb != nil || rq.queryParamsValidatorCb != nil ||
+		rq.contextValidatorCb != nil || rq.headersValidatorCb != nil ||
+		rq.cookiesValidatorCb != nil || rq.formValidatorCb != nil ||
+		rq.multipartValidatorCb != nil
+}
+
 // reflected key value pair
 type reflectedKeyVal struct {
 	// Field
@@ -80,18 +160,55 @@ type reflectedKeyVal struct {
 	aliasIsSet  bool
 	optional    bool
 	description string
+
+	// Value set when the param is missing, invalid if there is no default
+	defaultValue reflect.Value
+
+	// Index of the field in the section struct
+	index []int
+	// Setter of the raw values, nil for the values bound as is
+	set valuesSetter
+
+	// Validation, nil if the field has no constraints
+	constraints *fieldConstraints
 }
 
-// helper function to reflect the request struct
+// helper function to reflect the request struct.
+// Prints the errors and panics if the struct is misconfigured
 func ReflectReq[T any]() reflectedReq {
-	var v T
-	var errs []error
-	t := reflect.TypeOf(v)
+	reflected, err := reflectReq[T]()
+	if err != nil {
+		panicReflectErrors(err)
+	}
+	return reflected
+}
+
+// TryReflectReq reflects the request struct like ReflectReq, but returns
+// every misconfiguration as ReflectErrors instead of panicking
+func TryReflectReq[T any]() (RequestSchema, error) {
+	reflected, err := reflectReq[T]()
+	if err != nil {
+		return RequestSchema{}, err
+	}
+	return RequestSchema{reflected: reflected}, nil
+}
+
+func reflectReq[T any]() (reflectedReq, error) {
+	var reflectErrs []ReflectError
+	t := reflect.TypeOf((*T)(nil)).Elem()
 	isPtr := t.Kind() == reflect.Ptr
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
 
+	// only structs have sections
+	if t.Kind() != reflect.Struct {
+		return reflectedReq{typ: t, isPtr: isPtr}, ReflectErrors{
+			Type:   t.String(),
+			Errors: []ReflectError{{Err: errors.Join(ErrorUnsuppType, fmt.Errorf("request should be a struct or a pointer to a struct, got %s", t))}},
+		}
+	}
+
 	// Create a reflectedReq struct
 	reflected := reflectedReq{
 		typ:   t,
@@ -102,60 +219,102 @@ func ReflectReq[T any]() reflectedReq {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		tag := field.Tag.Get(_EZAPI_TAG_NAME)
+		var errs []ReflectError
 
 		// If the field has the
 		if tag != "" {
 			switch tag {
 			case _EZAPI_TAG_JSON_BODY:
-				if reflected.hasJSONBody() {
-					errs = append(errs, fmt.Errorf("NOW allow only one jsonBody tag per struct"))
-					continue
+				if reflected.readsBody() {
+					errs = append(errs, ReflectError{Err: fmt.Errorf("NOW allow only one body tag (jsonBody, body, form or multipart) per struct")})
+					break
 				}
 				reflected.jsonBodyType = field.Type
 				reflected.jsonBodyFieldName = field.Name
 				reflected.jsonBodyValidatorCb = getValidatorCallback(field.Type, field.Name)
+				reflected.bodyFields, errs = reflectBodyFields(field.Type)
+			case _EZAPI_TAG_BODY:
+				if reflected.readsBody() {
+					errs = append(errs, ReflectError{Err: fmt.Errorf("NOW allow only one body tag (jsonBody, body, form or multipart) per struct")})
+					break
+				}
+				reflected.bodyType = field.Type
+				reflected.bodyFieldName = field.Name
+				reflected.bodyValidatorCb = getValidatorCallback(field.Type, field.Name)
+				reflected.bodyFields, errs = reflectBodyFields(field.Type)
 			case _EZAPI_TAG_PATH_PARAMS:
 				if reflected.hasPathParams() {
-					errs = append(errs, fmt.Errorf("NOW allow only one path tag per struct"))
-					continue
+					errs = append(errs, ReflectError{Err: fmt.Errorf("NOW allow only one path tag per struct")})
+					break
 				}
 				reflected.pathParamsType = field.Type
 				reflected.pathParamsFieldName = field.Name
-				reflected.pathParams, errs = reflectParams(field.Type)
+				reflected.pathParams, errs = reflectParams(field.Type, _EZAPI_TAG_PATH_PARAMS)
 				reflected.pathParamsValidatorCb = getValidatorCallback(field.Type, field.Name)
 			case _EZAPI_TAG_QUERY_PARAMS:
 				if reflected.hasQueryParams() {
-					errs = append(errs, fmt.Errorf("NOW allow only one query tag per struct"))
-					continue
+					errs = append(errs, ReflectError{Err: fmt.Errorf("NOW allow only one query tag per struct")})
+					break
 				}
 				reflected.queryParamsType = field.Type
 				reflected.queryParamsFieldName = field.Name
-				reflected.queryParams, errs = reflectParams(field.Type)
+				reflected.queryParams, errs = reflectParams(field.Type, _EZAPI_TAG_QUERY_PARAMS)
 				reflected.queryParamsValidatorCb = getValidatorCallback(field.Type, field.Name)
 			case _EZAPI_TAG_CONTEXT:
 				if reflected.hasContextValues() {
-					errs = append(errs, fmt.Errorf("NOW allow only one context tag per struct"))
-					continue
+					errs = append(errs, ReflectError{Err: fmt.Errorf("NOW allow only one context tag per struct")})
+					break
 				}
 				reflected.contextValuesType = field.Type
 				reflected.contextValuesName = field.Name
-				reflected.contextValues, errs = reflectParams(field.Type)
+				reflected.contextValues, errs = reflectParams(field.Type, _EZAPI_TAG_CONTEXT)
 				reflected.contextValidatorCb = getValidatorCallback(field.Type, field.Name)
+			case _EZAPI_TAG_HEADERS:
+				if reflected.hasHeaders() {
+					errs = append(errs, ReflectError{Err: fmt.Errorf("NOW allow only one header tag per struct")})
+					break
+				}
+				reflected.headersType = field.Type
+				reflected.headersFieldName = field.Name
+				reflected.headers, errs = reflectParams(field.Type, _EZAPI_TAG_HEADERS)
+				reflected.headersValidatorCb = getValidatorCallback(field.Type, field.Name)
+			case _EZAPI_TAG_COOKIES:
+				if reflected.hasCookies() {
+					errs = append(errs, ReflectError{Err: fmt.Errorf("NOW allow only one cookie tag per struct")})
+					break
+				}
+				reflected.cookiesType = field.Type
+				reflected.cookiesFieldName = field.Name
+				reflected.cookies, errs = reflectParams(field.Type, _EZAPI_TAG_COOKIES)
+				reflected.cookiesValidatorCb = getValidatorCallback(field.Type, field.Name)
+			case _EZAPI_TAG_FORM:
+				if reflected.readsBody() {
+					errs = append(errs, ReflectError{Err: fmt.Errorf("NOW allow only one body tag (jsonBody, body, form or multipart) per struct")})
+					break
+				}
+				reflected.formType = field.Type
+				reflected.formFieldName = field.Name
+				reflected.form, errs = reflectParams(field.Type, _EZAPI_TAG_FORM)
+				reflected.formValidatorCb = getValidatorCallback(field.Type, field.Name)
+			case _EZAPI_TAG_MULTIPART:
+				if reflected.readsBody() {
+					errs = append(errs, ReflectError{Err: fmt.Errorf("NOW allow only one body tag (jsonBody, body, form or multipart) per struct")})
+					break
+				}
+				reflected.multipartType = field.Type
+				reflected.multipartFieldName = field.Name
+				reflected.multipart, errs = reflectParams(field.Type, _EZAPI_TAG_MULTIPART)
+				reflected.multipartValidatorCb = getValidatorCallback(field.Type, field.Name)
 			}
 		}
 
-		// If there are errors, panic
-		if len(errs) > 0 {
-			for i, err := range errs {
-				errStr := err.Error()
-				errStr = strings.ReplaceAll(errStr, "\n", "\n| ")
-				fmt.Printf("Error %d: %s\n\n", i+1, errStr)
-			}
-			panic(fmt.Sprintf("error reflecting request struct '%s'", t.Name()))
-		}
+		reflectErrs = append(reflectErrs, sectionReflectErrors(field, errs)...)
 	}
 
-	return reflected
+	if len(reflectErrs) > 0 {
+		return reflected, ReflectErrors{Type: t.Name(), Errors: reflectErrs}
+	}
+	return reflected, nil
 }
 
 func getIsValidatable(t reflect.Type) bool {
@@ -180,14 +339,14 @@ func getValidatorCallback(t reflect.Type, fieldName string) func(any, BaseContex
 	}
 }
 
-// helper function to reflect the parameters of a struct
-func reflectParams(t reflect.Type) ([]reflectedKeyVal, []error) {
-	var errs []error
+// helper function to reflect the parameters of a struct of the section
+func reflectParams(t reflect.Type, section string) ([]reflectedKeyVal, []ReflectError) {
+	var reflectErrs []ReflectError
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
 	if t.Kind() != reflect.Struct {
-		return nil, []error{errors.Join(ErrInvalidParamsType, fmt.Errorf("expected struct, got %s", t.Kind()))}
+		return nil, []ReflectError{{Err: errors.Join(ErrInvalidParamsType, fmt.Errorf("expected struct, got %s", t.Kind()))}}
 	}
 	params := []reflectedKeyVal{}
 
@@ -200,60 +359,223 @@ func reflectParams(t reflect.Type) ([]reflectedKeyVal, []error) {
 			continue
 		}
 
+		var errs []error
 		param := reflectedKeyVal{
 			typ:         field.Type,
 			fieldName:   field.Name,
+			index:       field.Index,
 			alias:       field.Name, // TODO: add warning if alias is not set
 			aliasIsSet:  false,
 			optional:    false,
 			description: fmt.Sprintf("The %s parameter", field.Name),
 		}
 
+		// compile the setter of the raw values once
+		switch {
+		case section == _EZAPI_TAG_MULTIPART && (param.typ == fileHeaderType || param.typ == fileHeadersType):
+			// uploaded files are bound as is
+		case section == _EZAPI_TAG_COOKIES && (param.typ == cookieType || param.typ == cookiePtrType):
+			// full cookies are bound as is
+		default:
+			set, err := compileValuesSetter(param.typ)
+			// context values may be stored with the field type, they need no setter
+			if err != nil && section != _EZAPI_TAG_CONTEXT {
+				errs = append(errs, err)
+			}
+			param.set = set
+		}
+
 		// tag values
-		tagValues := strings.Split(tag, ",")
-		for _, tagValue := range tagValues {
-			switch tagValue {
-			case _EZAPI_TAG_OPTIONAL:
-				param.optional = true
-			case _EZAPI_TAG_REQUIRED:
-				param.optional = false
-			default:
-				var name, value string
-				nameValue := strings.Split(tagValue, "=")
-				if len(nameValue) == 1 && !param.aliasIsSet {
-					param.alias = nameValue[0]
-					param.aliasIsSet = true
-					continue
-				}
+		parsed, tagErrs := parseParamTag(tag)
+		errs = append(errs, tagErrs...)
+		if parsed.aliasIsSet {
+			param.alias = parsed.alias
+			param.aliasIsSet = true
+		}
+		if parsed.description != "" {
+			param.description = parsed.description
+		}
+		param.optional = parsed.optional
+		param.constraints = parsed.constraints
+		if parsed.hasDefault {
+			defaultValue, err := parseDefault(param, parsed.defaultValue)
+			if err != nil {
+				errs = append(errs, errors.Join(ErrInvalidTag, fmt.Errorf("invalid default '%s'", parsed.defaultValue), err))
+			} else {
+				param.defaultValue = defaultValue
+			}
+		}
 
-				if len(nameValue) >= 1 {
-					name = nameValue[0]
-				}
-				if len(nameValue) >= 2 {
-					value = nameValue[1]
+		if param.constraints != nil {
+			if err := param.constraints.validateType(param.typ); err != nil {
+				errs = append(errs, errors.Join(ErrInvalidTag, err))
+			} else if param.hasDefault() {
+				if err := param.constraints.check(param.defaultValue); err != nil {
+					errs = append(errs, errors.Join(ErrInvalidTag, fmt.Errorf("invalid default"), err))
 				}
+			}
+		}
+
+		// a param with a default is never missing
+		if param.hasDefault() {
+			param.optional = true
+		}
+
+		for _, err := range errs {
+			reflectErrs = append(reflectErrs, ReflectError{Field: field.Name, Tag: tag, Err: err})
+		}
+		params = append(params, param)
+	}
+
+	return params, reflectErrs
+}
 
-				if name == "" || value == "" {
-					errs = append(errs, errors.Join(ErrInvalidTag, fmt.Errorf("should be in the format key=value, got '%s'", tagValue)))
+// the type independent part of the param tag
+type paramTag struct {
+	alias        string
+	aliasIsSet   bool
+	optional     bool
+	description  string
+	defaultValue string
+	hasDefault   bool
+	constraints  *fieldConstraints
+}
+
+// helper function to parse the tag of the param
+func parseParamTag(tag string) (paramTag, []error) {
+	var errs []error
+	var param paramTag
+	tagValues := splitTag(tag)
+	for _, tagValue := range tagValues {
+		switch tagValue {
+		case _EZAPI_TAG_OPTIONAL:
+			param.optional = true
+		case _EZAPI_TAG_REQUIRED:
+			param.optional = false
+		default:
+			var name, value string
+			nameValue := strings.SplitN(tagValue, "=", 2)
+			if len(nameValue) == 1 && !param.aliasIsSet {
+				param.alias = nameValue[0]
+				param.aliasIsSet = true
+				continue
+			}
+
+			if len(nameValue) >= 1 {
+				name = nameValue[0]
+			}
+			if len(nameValue) >= 2 {
+				value = nameValue[1]
+			}
+
+			if name == "" || value == "" {
+				errs = append(errs, errors.Join(ErrInvalidTag, fmt.Errorf("should be in the format key=value, got '%s'", tagValue)))
+				continue
+			}
+			switch name {
+			case _EZAPI_TAG_ALIAS:
+				param.alias = value
+				param.aliasIsSet = true
+			case _EZAPI_TAG_DESC:
+				param.description = value
+			case _EZAPI_TAG_DEFAULT:
+				param.defaultValue = value
+				param.hasDefault = true
+			default:
+				if param.constraints == nil {
+					param.constraints = &fieldConstraints{}
+				}
+				handled, err := parseConstraint(param.constraints, name, value)
+				if err != nil {
+					errs = append(errs, err)
 					continue
 				}
-				switch name {
-				case _EZAPI_TAG_ALIAS:
-					param.alias = value
-					param.aliasIsSet = true
-				case _EZAPI_TAG_DESC:
-					param.description = value
-				default:
-					errs = append(errs, fmt.Errorf("unknown tag value '%s'", name))
+				if !handled {
+					errs = append(errs, errors.Join(ErrInvalidTag, fmt.Errorf("unknown tag value '%s'", name)))
 					continue
 				}
 			}
 		}
+	}
+	return param, errs
+}
 
-		params = append(params, param)
+// split the tag on the commas. The pattern consumes the rest of the tag,
+// so it can contain commas, e.g. pattern=^\d{1,3}$
+func splitTag(tag string) []string {
+	tagValues := strings.Split(tag, ",")
+	for i, tagValue := range tagValues {
+		if strings.HasPrefix(tagValue, _EZAPI_TAG_PATTERN+"=") {
+			return append(tagValues[:i], strings.Join(tagValues[i:], ","))
+		}
 	}
+	return tagValues
+}
 
-	return params, errs
+// helper function to convert the default tag value once, at startup.
+// The items of a slice default are separated by '|'
+func parseDefault(param reflectedKeyVal, value string) (reflect.Value, error) {
+	if param.set == nil {
+		return reflect.Value{}, fmt.Errorf("%w: %s can't have a default", ErrorUnsuppType, param.typ)
+	}
+	values := []string{value}
+	if param.typ.Kind() == reflect.Slice && param.typ.Elem().Kind() != reflect.Uint8 {
+		values = strings.Split(value, "|")
+	}
+	v := reflect.New(param.typ).Elem()
+	if err := param.set(v, values); err != nil {
+		return reflect.Value{}, err
+	}
+	return v, nil
+}
+
+// details of the param for the error chain, built only when the binding fails
+func (p *reflectedKeyVal) debugErr() error {
+	return fmt.Errorf("alias: %s, field: %s", p.alias, p.fieldName)
+}
+
+func (p reflectedKeyVal) hasDefault() bool {
+	return p.defaultValue.IsValid()
+}
+
+// reports whether the param may be missing, its empty value is treated as missing
+func (p reflectedKeyVal) omittable() bool {
+	return p.optional || p.hasDefault()
+}
+
+// copy of the default value, so the handlers can't change it between requests
+func (p reflectedKeyVal) defaultCopy() reflect.Value {
+	return deepCopy(p.defaultValue)
+}
+
+// copy the pointees and the items of the slices, e.g. of []*int
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	default:
+		return v
+	}
 }
 
 var (
@@ -0,0 +1,131 @@
+package ezapi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTryReflectReqNonStruct(t *testing.T) {
+	for name, try := range map[string]func() error{
+		"int":     func() error { _, err := TryReflectReq[int](); return err },
+		"any":     func() error { _, err := TryReflectReq[any](); return err },
+		"*string": func() error { _, err := TryReflectReq[*string](); return err },
+	} {
+		err := try()
+		var reflectErrs ReflectErrors
+		if !errors.As(err, &reflectErrs) {
+			t.Fatalf("%s: expected ReflectErrors, got %v", name, err)
+		}
+		if !errors.Is(err, ErrorUnsuppType) {
+			t.Errorf("%s: expected ErrorUnsuppType, got %v", name, err)
+		}
+	}
+
+	_, err := TryH(func(Context[int]) (string, RespError) { return "", nil })
+	if !errors.Is(err, ErrorUnsuppType) {
+		t.Errorf("TryH: expected ErrorUnsuppType, got %v", err)
+	}
+}
+
+type badPatternReq struct {
+	Query struct {
+		Code string `ezapi:"code,pattern=^(\\d{1,3}$"`
+	} `ezapi:"query"`
+}
+
+func TestPatternWithCommas(t *testing.T) {
+	for _, section := range []Section{SectionQuery, SectionJSONBody} {
+		tag, err := ParseTag(section, `desc=code,pattern=^\d{1,3}$`)
+		if err != nil {
+			t.Fatalf("%s: %v", section, err)
+		}
+		if tag.Description != "code" {
+			t.Errorf("%s: expected the values before the pattern to be parsed, got %q", section, tag.Description)
+		}
+		if tag.Constraints == nil || tag.Constraints.Pattern != `^\d{1,3}$` {
+			t.Errorf("%s: expected the pattern to keep its commas, got %+v", section, tag.Constraints)
+		}
+	}
+
+	_, err := TryReflectReq[badPatternReq]()
+	var reflectErrs ReflectErrors
+	if !errors.As(err, &reflectErrs) || !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected a ReflectError for the malformed pattern, got %v", err)
+	}
+}
+
+type bodyFieldsReq struct {
+	JSONBody struct {
+		Title string `json:"title" ezapi:"minLen=1"`
+		Done  bool   `json:"done,omitempty"`
+		Notes string
+		Skip  string `json:"-"`
+	} `ezapi:"jsonBody"`
+}
+
+func TestBodySectionFields(t *testing.T) {
+	schema, err := TryReflectReq[bodyFieldsReq]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	section, ok := schema.Section(SectionJSONBody)
+	if !ok {
+		t.Fatal("expected the json body section")
+	}
+	var aliases []string
+	for _, field := range section.Fields {
+		aliases = append(aliases, field.Alias)
+		if constrained := field.Constraints != nil; constrained != (field.Alias == "title") {
+			t.Errorf("%s: unexpected constraints %+v", field.Alias, field.Constraints)
+		}
+	}
+	if strings.Join(aliases, ",") != "title,done,Notes" {
+		t.Errorf("expected every json field, got %v", aliases)
+	}
+}
+
+type unsupportedFieldsReq struct {
+	Query struct {
+		Filter map[string]int `ezapi:"filter"`
+		Limit  int            `ezapi:"limit,optional"`
+	} `ezapi:"query"`
+	Path struct {
+		Owner struct{ Name string } `ezapi:"owner"`
+	} `ezapi:"path"`
+	Headers struct {
+		Trace chan int `ezapi:"X-Trace"`
+	} `ezapi:"header"`
+}
+
+func TestTryHUnsupportedFields(t *testing.T) {
+	handler, err := TryH(func(Context[unsupportedFieldsReq]) (string, RespError) { return "", nil })
+	if handler != nil {
+		t.Error("expected no handler for the unsupported fields")
+	}
+	var reflectErrs ReflectErrors
+	if !errors.As(err, &reflectErrs) {
+		t.Fatalf("expected ReflectErrors, got %v", err)
+	}
+	// every unsupported field is reported at once
+	var fields []string
+	for _, reflectErr := range reflectErrs.Errors {
+		fields = append(fields, reflectErr.Field)
+		if !errors.Is(reflectErr, ErrorUnsuppType) {
+			t.Errorf("%s: expected ErrorUnsuppType, got %v", reflectErr.Field, reflectErr.Err)
+		}
+	}
+	want := []string{"Query.Filter", "Path.Owner", "Headers.Trace"}
+	if !slices.Equal(fields, want) {
+		t.Errorf("expected the errors of %q, got %q", want, fields)
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "unsupportedFieldsReq") {
+			t.Errorf("expected H to panic with the registration errors, got %v", r)
+		}
+	}()
+	H(func(Context[unsupportedFieldsReq]) (string, RespError) { return "", nil })
+}
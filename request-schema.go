@@ -0,0 +1,213 @@
+package ezapi
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Section is the part of the request a struct field is bound from
+type Section string
+
+const (
+	SectionJSONBody  Section = _EZAPI_TAG_JSON_BODY
+	SectionBody      Section = _EZAPI_TAG_BODY
+	SectionPath      Section = _EZAPI_TAG_PATH_PARAMS
+	SectionQuery     Section = _EZAPI_TAG_QUERY_PARAMS
+	SectionContext   Section = _EZAPI_TAG_CONTEXT
+	SectionHeader    Section = _EZAPI_TAG_HEADERS
+	SectionCookie    Section = _EZAPI_TAG_COOKIES
+	SectionForm      Section = _EZAPI_TAG_FORM
+	SectionMultipart Section = _EZAPI_TAG_MULTIPART
+)
+
+// RequestSchema is the reflected request struct, returned by TryReflectReq.
+// It is read-only, every accessor returns a copy
+type RequestSchema struct {
+	reflected reflectedReq
+}
+
+func (s RequestSchema) String() string {
+	return s.reflected.String()
+}
+
+// Type of the request struct, never a pointer
+func (s RequestSchema) Type() reflect.Type {
+	return s.reflected.typ
+}
+
+// IsPtr reports whether the handler receives a pointer to the request struct
+func (s RequestSchema) IsPtr() bool {
+	return s.reflected.isPtr
+}
+
+// HasValidator reports whether the request implements Validatable
+func (s RequestSchema) HasValidator() bool {
+	if s.reflected.typ == nil {
+		return false
+	}
+	t := s.reflected.typ
+	if s.reflected.isPtr {
+		t = reflect.PointerTo(t)
+	}
+	return getIsValidatable(t)
+}
+
+// Sections of the request in the order they are bound
+func (s RequestSchema) Sections() []SectionSchema {
+	rq := s.reflected
+	var sections []SectionSchema
+	add := func(section Section, fieldName string, typ reflect.Type, cb func(any, BaseContext) RespError, params []reflectedKeyVal) {
+		if typ == nil {
+			return
+		}
+		fields := make([]FieldSchema, len(params))
+		for i, p := range params {
+			fields[i] = p.schema()
+		}
+		sections = append(sections, SectionSchema{
+			Section:      section,
+			FieldName:    fieldName,
+			Type:         typ,
+			HasValidator: cb != nil,
+			Fields:       fields,
+		})
+	}
+	add(SectionPath, rq.pathParamsFieldName, rq.pathParamsType, rq.pathParamsValidatorCb, rq.pathParams)
+	add(SectionQuery, rq.queryParamsFieldName, rq.queryParamsType, rq.queryParamsValidatorCb, rq.queryParams)
+	add(SectionContext, rq.contextValuesName, rq.contextValuesType, rq.contextValidatorCb, rq.contextValues)
+	add(SectionHeader, rq.headersFieldName, rq.headersType, rq.headersValidatorCb, rq.headers)
+	add(SectionCookie, rq.cookiesFieldName, rq.cookiesType, rq.cookiesValidatorCb, rq.cookies)
+	add(SectionForm, rq.formFieldName, rq.formType, rq.formValidatorCb, rq.form)
+	add(SectionMultipart, rq.multipartFieldName, rq.multipartType, rq.multipartValidatorCb, rq.multipart)
+	add(SectionBody, rq.bodyFieldName, rq.bodyType, rq.bodyValidatorCb, rq.bodyFields)
+	add(SectionJSONBody, rq.jsonBodyFieldName, rq.jsonBodyType, rq.jsonBodyValidatorCb, rq.bodyFields)
+	return sections
+}
+
+// Section returns the schema of the section, false if the request has no such section
+func (s RequestSchema) Section(section Section) (SectionSchema, bool) {
+	for _, sectionSchema := range s.Sections() {
+		if sectionSchema.Section == section {
+			return sectionSchema, true
+		}
+	}
+	return SectionSchema{}, false
+}
+
+// SectionSchema is a tagged field of the request struct, e.g. `ezapi:"query"`
+type SectionSchema struct {
+	Section Section
+	// Name of the Go field of the section
+	FieldName string
+	// Type of the section field, pointer types are kept
+	Type reflect.Type
+	// The section implements Validatable
+	HasValidator bool
+	// The params of the section. For body sections, every json field of the
+	// body struct, the ones with constraints have non-nil Constraints
+	Fields []FieldSchema
+}
+
+// FieldSchema is a single param of the section
+type FieldSchema struct {
+	// Name of the Go field
+	FieldName string
+	Type      reflect.Type
+	// Name of the param in the request, e.g. the query key or the json name
+	Alias       string
+	Optional    bool
+	Description string
+	// Value used when the param is missing, nil if there is no default
+	Default any
+	// Nil if the field has no constraints
+	Constraints *Constraints
+}
+
+// Constraints of the field from the ezapi tag, nil values are not set
+type Constraints struct {
+	Min     *float64
+	Max     *float64
+	MinLen  *int
+	MaxLen  *int
+	Pattern string
+	Enum    []string
+	Format  string
+}
+
+// public copy of the param
+func (p reflectedKeyVal) schema() FieldSchema {
+	field := FieldSchema{
+		FieldName:   p.fieldName,
+		Type:        p.typ,
+		Alias:       p.alias,
+		Optional:    p.optional,
+		Description: p.description,
+	}
+	if p.hasDefault() {
+		field.Default = p.defaultCopy().Interface()
+	}
+	field.Constraints = p.constraints.public()
+	return field
+}
+
+// public copy of the constraints, nil if there are none
+func (c *fieldConstraints) public() *Constraints {
+	if c == nil {
+		return nil
+	}
+	constraints := &Constraints{
+		Min:    copyPtr(c.min),
+		Max:    copyPtr(c.max),
+		MinLen: copyPtr(c.minLen),
+		MaxLen: copyPtr(c.maxLen),
+		Enum:   append([]string(nil), c.enum...),
+		Format: c.format,
+	}
+	if c.pattern != nil {
+		constraints.Pattern = c.pattern.String()
+	}
+	return constraints
+}
+
+// TagSchema is the parsed ezapi tag of a param or of a body field
+type TagSchema struct {
+	// Empty if the tag sets no alias, the field name is used then
+	Alias       string
+	Optional    bool
+	Description string
+	// Raw default value, the items of a slice default are separated by '|'
+	Default     string
+	HasDefault  bool
+	Constraints *Constraints
+}
+
+// ParseTag parses the ezapi tag of a field of the section struct, or of a
+// body field for the body sections. It is meant for the tools reading the
+// source code like `ezapi gen`, the field types are only checked by ReflectReq
+func ParseTag(section Section, tag string) (TagSchema, error) {
+	if section == SectionJSONBody || section == SectionBody {
+		constraints, description, errs := parseConstraints(tag)
+		return TagSchema{
+			Description: description,
+			Constraints: constraints.public(),
+		}, errors.Join(errs...)
+	}
+
+	parsed, errs := parseParamTag(tag)
+	return TagSchema{
+		Alias:       parsed.alias,
+		Optional:    parsed.optional || parsed.hasDefault,
+		Description: parsed.description,
+		Default:     parsed.defaultValue,
+		HasDefault:  parsed.hasDefault,
+		Constraints: parsed.constraints.public(),
+	}, errors.Join(errs...)
+}
+
+func copyPtr[V any](v *V) *V {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
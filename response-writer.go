@@ -0,0 +1,127 @@
+package ezapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+)
+
+// responseWriter wraps the http.ResponseWriter of H. It tracks whether
+// the status is sent and ignores the repeated WriteHeader calls, so an error
+// rendered after the response is committed doesn't corrupt it. It forwards
+// http.Flusher, http.Hijacker, http.Pusher and io.ReaderFrom, the other
+// interfaces are reached with http.ResponseController through Unwrap
+type responseWriter struct {
+	http.ResponseWriter
+
+	// sent status, 0 until the response is committed
+	status int
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+	// informational responses can precede the final one
+	if status >= 200 {
+		w.status = status
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush commits the response and flushes it if the underlying writer supports it
+func (w *responseWriter) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection if the underlying writer supports it. The
+// response is committed then, the errors can't be rendered anymore
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Push pushes the resource if the underlying writer supports HTTP/2 server
+// push, http.ErrNotSupported otherwise
+func (w *responseWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := w.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// ReadFrom commits the response and copies the reader with the io.ReaderFrom
+// of the underlying writer if it has one, e.g. sendfile for *os.File
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if readerFrom, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		return readerFrom.ReadFrom(r)
+	}
+	// only the Write of the underlying writer, io.Copy would call ReadFrom again
+	return io.Copy(struct{ io.Writer }{w.ResponseWriter}, r)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// reports whether the status of the response is already sent
+func isCommitted(w http.ResponseWriter) bool {
+	rw, ok := w.(*responseWriter)
+	return ok && rw.status != 0
+}
+
+// helper function to write the json body. It is encoded before the headers
+// are sent, so an encoding failure leaves the response uncommitted
+func writeJSON(w http.ResponseWriter, status int, contentType string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, err = w.Write(append(body, '\n'))
+	return err
+}
+
+// statusWriter sends its status instead of the one the renderable body of a
+// Response writes, the headers set by the body are kept
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w statusWriter) WriteHeader(int) {
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w statusWriter) Write(b []byte) (int, error) {
+	// the repeated WriteHeader calls are ignored by the responseWriter
+	w.ResponseWriter.WriteHeader(w.status)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,88 @@
+package ezapi
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Response lets the handler set the status code and headers while the body
+// is still encoded as a regular handler result
+type Response[U any] struct {
+	// Status code, it replaces the one written by a Renderable body
+	Status int
+	Header http.Header
+	Body   U
+}
+
+// WithStatus wraps the body with the status code
+func WithStatus[U any](status int, body U) Response[U] {
+	return Response[U]{Status: status, Body: body}
+}
+
+// WithHeaders wraps the body with the headers
+func WithHeaders[U any](header http.Header, body U) Response[U] {
+	return Response[U]{Header: header, Body: body}
+}
+
+// Created is a 201 response with the Location header. Set
+// SuccessStatus(http.StatusCreated) on the route to document the status
+func Created[U any](location string, body U) Response[U] {
+	return Response[U]{
+		Status: http.StatusCreated,
+		Header: http.Header{"Location": []string{location}},
+		Body:   body,
+	}
+}
+
+// NoContent is a 204 response without a body
+func NoContent() Response[struct{}] {
+	return Response[struct{}]{Status: http.StatusNoContent}
+}
+
+// SetHeader returns a copy of the response with the header set
+func (r Response[U]) SetHeader(key, value string) Response[U] {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(key, value)
+	r.Header = header
+	return r
+}
+
+// implemented by Response to be unwrapped in H
+type responseEnvelope interface {
+	envelopeStatus() int
+	envelopeHeader() http.Header
+	envelopeBody() any
+	envelopeBodyType() reflect.Type
+}
+
+func (r Response[U]) envelopeStatus() int {
+	return r.Status
+}
+
+func (r Response[U]) envelopeHeader() http.Header {
+	return r.Header
+}
+
+func (r Response[U]) envelopeBody() any {
+	return r.Body
+}
+
+func (r Response[U]) envelopeBodyType() reflect.Type {
+	return reflect.TypeOf((*U)(nil)).Elem()
+}
+
+// filled by Call with the status and the headers of the response
+func (r *Response[U]) decodeEnvelope(status int, header http.Header, decode func(any) error) error {
+	r.Status = status
+	r.Header = header
+	return decode(&r.Body)
+}
+
+// statuses that must not have a body
+func bodylessStatus(status int) bool {
+	return status == http.StatusNoContent || status == http.StatusNotModified ||
+		(status >= 100 && status < 200)
+}
@@ -0,0 +1,226 @@
+package ezapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Route is a handler registered in the Router with its metadata
+type Route struct {
+	Method  string
+	Pattern string
+
+	req         reflectedReq
+	respType    reflect.Type
+	contentType string
+	codecs      []Codec
+	status      int
+	errors      []routeError
+	registries  []*ErrorRegistry
+	problems    bool
+}
+
+// error response of the route with the type of its body
+type routeError struct {
+	status int
+	typ    reflect.Type
+}
+
+func (rt Route) String() string {
+	return fmt.Sprintf("%s %s (%v -> %v)", rt.Method, rt.Pattern, rt.req.typ, rt.respType)
+}
+
+// Request is the schema of the request struct of the route
+func (rt Route) Request() RequestSchema {
+	return RequestSchema{reflected: rt.req}
+}
+
+// ResponseType is the type of the response body, unwrapped from Response
+func (rt Route) ResponseType() reflect.Type {
+	return rt.respType
+}
+
+// error responses of the route: the ones of the default errors followed
+// by the documented ones, which replace the defaults with the same status.
+// The mappings of the error registries are read now, not at registration
+func (rt Route) errorResponses() []routeError {
+	var defaults []routeError
+	ezErrType := reflect.TypeOf(EzAPIError{})
+	if rt.problems {
+		ezErrType = problemType
+	}
+	// unmarshal errors are only possible if the request has inputs
+	if len(rt.req.pathParams) > 0 || len(rt.req.queryParams) > 0 || len(rt.req.headers) > 0 ||
+		len(rt.req.cookies) > 0 || rt.req.readsBody() {
+		defaults = append(defaults, routeError{status: http.StatusBadRequest, typ: ezErrType})
+	}
+	if rt.req.hasBody() {
+		defaults = append(defaults, routeError{status: http.StatusUnsupportedMediaType, typ: ezErrType})
+	}
+	// the failed field validations are combined into a 422
+	if rt.req.validates() {
+		defaults = append(defaults, routeError{status: http.StatusUnprocessableEntity, typ: ezErrType})
+	}
+	defaults = append(defaults, routeError{status: http.StatusInternalServerError, typ: ezErrType})
+
+	errs := append([]routeError(nil), rt.errors...)
+	for _, registry := range rt.registries {
+		errs = append(errs, registry.routeErrors()...)
+	}
+
+	responses := make([]routeError, 0, len(defaults)+len(errs))
+	for _, def := range defaults {
+		documented := false
+		for _, e := range errs {
+			documented = documented || e.status == def.status
+		}
+		if !documented {
+			responses = append(responses, def)
+		}
+	}
+	responses = append(responses, errs...)
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].status < responses[j].status
+	})
+	return responses
+}
+
+// Router registers ezapi handlers with their HTTP method and pattern.
+// Requests are served by http.ServeMux, so the patterns use its syntax
+// (e.g. "/todo/{id}")
+type Router struct {
+	*routerState
+
+	// group settings, inherited by the nested groups
+	prefix string
+	opts   []HandlerOpt
+}
+
+// state shared between the router and its groups
+type routerState struct {
+	mu  sync.Mutex
+	mux *http.ServeMux
+
+	routes []Route
+}
+
+func NewRouter(opts ...HandlerOpt) *Router {
+	return &Router{
+		routerState: &routerState{
+			mux: http.NewServeMux(),
+		},
+		opts: opts,
+	}
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+// Group creates a sub-router that prefixes the patterns with the given
+// prefix and applies the options before the options of each route.
+// The routes are served by the parent router
+func (r *Router) Group(prefix string, opts ...HandlerOpt) *Router {
+	groupOpts := make([]HandlerOpt, 0, len(r.opts)+len(opts))
+	groupOpts = append(groupOpts, r.opts...)
+	groupOpts = append(groupOpts, opts...)
+	return &Router{
+		routerState: r.routerState,
+		prefix:      r.prefix + strings.TrimSuffix(prefix, "/"),
+		opts:        groupOpts,
+	}
+}
+
+// Use adds middlewares to the router. They are applied to the handlers
+// registered after the call
+func (r *Router) Use(middlewares ...Middleware) {
+	r.opts = append(r.opts[:len(r.opts):len(r.opts)], Use(middlewares...))
+}
+
+// Handle registers a plain http.Handler. The pattern gets the group prefix
+// and the group middlewares are applied, but it is not recorded as a route
+func (r *Router) Handle(pattern string, handler http.Handler) {
+	options := newHandlerOpts()
+	for _, opt := range r.opts {
+		opt(options)
+	}
+	r.mux.Handle(r.pattern(pattern), options.wrap(handler))
+}
+
+// HandleFunc registers a plain http.HandlerFunc, see Handle
+func (r *Router) HandleFunc(pattern string, handler http.HandlerFunc) {
+	r.Handle(pattern, handler)
+}
+
+// Routes returns the registered routes in the registration order
+func (r *Router) Routes() []Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	routes := make([]Route, len(r.routes))
+	copy(routes, r.routes)
+	return routes
+}
+
+// OpenAPI builds the spec of the routes registered so far
+func (r *Router) OpenAPI(title, version string) *OpenAPISpec {
+	spec := NewOpenAPISpec(title, version)
+	for _, rt := range r.Routes() {
+		spec.add(rt)
+	}
+	return spec
+}
+
+func (r *routerState) addRoute(rt Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, rt)
+}
+
+// add the group prefix to the pattern, keeping the method if present
+func (r *Router) pattern(pattern string) string {
+	if r.prefix == "" {
+		return pattern
+	}
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		return method + " " + r.prefix + strings.TrimSpace(path)
+	}
+	return r.prefix + pattern
+}
+
+// Handle registers the handler for the given method and pattern
+func Handle[T any, U any](r *Router, method, pattern string, handler func(Context[T]) (U, RespError), opts ...HandlerOpt) {
+	pattern = r.pattern(pattern)
+	routeOpts := make([]HandlerOpt, 0, len(r.opts)+len(opts)+1)
+	routeOpts = append(routeOpts, r.opts...)
+	routeOpts = append(routeOpts, opts...)
+	routeOpts = append(routeOpts, onRoute(func(rt Route) {
+		rt.Method = method
+		rt.Pattern = pattern
+		r.addRoute(rt)
+	}))
+	r.mux.Handle(method+" "+pattern, H(handler, routeOpts...))
+}
+
+func GET[T any, U any](r *Router, pattern string, handler func(Context[T]) (U, RespError), opts ...HandlerOpt) {
+	Handle(r, http.MethodGet, pattern, handler, opts...)
+}
+
+func POST[T any, U any](r *Router, pattern string, handler func(Context[T]) (U, RespError), opts ...HandlerOpt) {
+	Handle(r, http.MethodPost, pattern, handler, opts...)
+}
+
+func PUT[T any, U any](r *Router, pattern string, handler func(Context[T]) (U, RespError), opts ...HandlerOpt) {
+	Handle(r, http.MethodPut, pattern, handler, opts...)
+}
+
+func PATCH[T any, U any](r *Router, pattern string, handler func(Context[T]) (U, RespError), opts ...HandlerOpt) {
+	Handle(r, http.MethodPatch, pattern, handler, opts...)
+}
+
+func DELETE[T any, U any](r *Router, pattern string, handler func(Context[T]) (U, RespError), opts ...HandlerOpt) {
+	Handle(r, http.MethodDelete, pattern, handler, opts...)
+}
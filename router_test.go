@@ -0,0 +1,98 @@
+package ezapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type routeItemReq struct {
+	Path struct {
+		ID int `ezapi:"id"`
+	} `ezapi:"path"`
+}
+
+// serve the request with the handler and record the response
+func serve(t *testing.T, h http.Handler, method, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestRouterMethodAndPattern(t *testing.T) {
+	r := NewRouter()
+	GET(r, "/items/{id}", func(ctx Context[routeItemReq]) (int, RespError) {
+		return ctx.GetReq().Path.ID, nil
+	})
+	POST(r, "/items", func(Context[struct{}]) (string, RespError) { return "created", nil })
+
+	if w := serve(t, r, http.MethodGet, "/items/42"); w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "42" {
+		t.Errorf("expected the bound path param, got %d: %s", w.Code, w.Body)
+	}
+	if w := serve(t, r, http.MethodPost, "/items"); w.Code != http.StatusOK || w.Body.String() != "created" {
+		t.Errorf("expected the POST handler, got %d: %s", w.Code, w.Body)
+	}
+	if w := serve(t, r, http.MethodDelete, "/items/42"); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for the unregistered method, got %d", w.Code)
+	}
+
+	routes := r.Routes()
+	if len(routes) != 2 ||
+		routes[0].Method != http.MethodGet || routes[0].Pattern != "/items/{id}" ||
+		routes[1].Method != http.MethodPost || routes[1].Pattern != "/items" {
+		t.Errorf("expected the routes in the registration order, got %v", routes)
+	}
+}
+
+// middleware appending its name to the X-Order header before the handler runs
+func orderMiddleware(name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Order", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestGroupPrefixAndOptions(t *testing.T) {
+	r := NewRouter()
+	api := r.Group("/api/", SuccessStatus(http.StatusAccepted))
+	v1 := api.Group("/v1")
+	GET(v1, "/jobs", func(Context[struct{}]) (string, RespError) { return "queued", nil })
+	POST(v1, "/jobs", func(Context[struct{}]) (string, RespError) { return "created", nil },
+		SuccessStatus(http.StatusCreated))
+
+	if w := serve(t, r, http.MethodGet, "/api/v1/jobs"); w.Code != http.StatusAccepted {
+		t.Errorf("expected the status of the group, got %d: %s", w.Code, w.Body)
+	}
+	if w := serve(t, r, http.MethodPost, "/api/v1/jobs"); w.Code != http.StatusCreated {
+		t.Errorf("expected the route option to override the group, got %d: %s", w.Code, w.Body)
+	}
+	if routes := r.Routes(); len(routes) != 2 || routes[0].Pattern != "/api/v1/jobs" {
+		t.Errorf("expected the prefixed patterns, got %v", routes)
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	r := NewRouter(Use(orderMiddleware("router")))
+	group := r.Group("/g", Use(orderMiddleware("group")))
+	group.Use(orderMiddleware("group-use"))
+	GET(group, "/route", func(Context[struct{}]) (string, RespError) { return "", nil },
+		Use(orderMiddleware("route")))
+	group.HandleFunc("GET /plain", func(http.ResponseWriter, *http.Request) {})
+	// applied only to the handlers registered afterwards
+	group.Use(orderMiddleware("late"))
+
+	for _, target := range []string{"/g/route", "/g/plain"} {
+		want := []string{"router", "group", "group-use"}
+		if target == "/g/route" {
+			want = append(want, "route")
+		}
+		w := serve(t, r, http.MethodGet, target)
+		if got := w.Header()["X-Order"]; strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: expected the middlewares in the order %v, got %v", target, want, got)
+		}
+	}
+}
@@ -6,14 +6,24 @@ func (r reflectedReq) String() string {
 	return fmt.Sprintf(`EzAPI Reflected Request:
 	NAME: %v
 	JSON Body: %v
+	Body: %v
 	Path Params: %v
 	Query Params: %v
-	Context Values: %v`,
+	Context Values: %v
+	Headers: %v
+	Cookies: %v
+	Form: %v
+	Multipart: %v`,
 		r.typ.Name(),
 		r.jsonBodyType,
+		r.bodyType,
 		r.pathParams,
 		r.queryParams,
 		r.contextValues,
+		r.headers,
+		r.cookies,
+		r.form,
+		r.multipart,
 	)
 }
 
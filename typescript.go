@@ -0,0 +1,598 @@
+package ezapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// TypeScript generates the TypeScript client of the routes registered so far
+func (r *Router) TypeScript() []byte {
+	return GenerateTypeScript(r.Routes()...)
+}
+
+// GenerateTypeScript emits the TypeScript interfaces of the request, response
+// and error bodies of the routes, and a fetch based client with one function
+// per route. The function is named after the request struct, e.g.
+// CreateTodoReq becomes createTodo
+func GenerateTypeScript(routes ...Route) []byte {
+	g := newTSGenerator()
+
+	var funcs bytes.Buffer
+	names := map[string]bool{}
+	for _, rt := range routes {
+		name := tsFuncName(rt)
+		for i := 2; names[name]; i++ {
+			name = tsFuncName(rt) + strconv.Itoa(i)
+		}
+		names[name] = true
+		g.writeRoute(&funcs, rt, name)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by ezapi. DO NOT EDIT.\n\n")
+	for _, name := range g.order {
+		out.WriteString(g.decls[name])
+		out.WriteString("\n")
+	}
+	out.WriteString(_TS_RUNTIME)
+	out.Write(funcs.Bytes())
+	return out.Bytes()
+}
+
+// helpers shared by the route functions
+const _TS_RUNTIME = `export interface ClientOptions {
+  // Base URL of the server, e.g. "http://localhost:8080"
+  baseUrl: string;
+  // Sent with every request, e.g. Authorization
+  headers?: Record<string, string>;
+  // globalThis.fetch if not set
+  fetch?: typeof fetch;
+}
+
+// ApiError is thrown for the responses with a non-2xx status
+export class ApiError<S extends number = number, E = unknown> extends Error {
+  constructor(
+    readonly status: S,
+    readonly body: E,
+    readonly response: Response,
+  ) {
+    super(` + "`${status} ${response.statusText}`" + `);
+  }
+}
+
+type Param = string | number | boolean | bigint;
+
+function appendParam(add: (key: string, value: string) => void, key: string, value: Param | Param[] | null | undefined): void {
+  if (value === undefined || value === null) {
+    return;
+  }
+  for (const item of Array.isArray(value) ? value : [value]) {
+    add(key, String(item));
+  }
+}
+
+function appendFile(form: FormData, key: string, value: Blob | Blob[] | null | undefined): void {
+  if (value === undefined || value === null) {
+    return;
+  }
+  for (const item of Array.isArray(value) ? value : [value]) {
+    form.append(key, item);
+  }
+}
+
+function pathSegment(value: Param, rest: boolean): string {
+  const s = String(value);
+  return rest ? s.split("/").map(encodeURIComponent).join("/") : encodeURIComponent(s);
+}
+
+async function readBody(resp: Response): Promise<unknown> {
+  const text = await resp.text();
+  if (text === "") {
+    return undefined;
+  }
+  const contentType = resp.headers.get("Content-Type") ?? "";
+  if (/^application\/([\w.-]+\+)?json/.test(contentType)) {
+    return JSON.parse(text);
+  }
+  return text;
+}
+
+async function send(
+  opts: ClientOptions,
+  method: string,
+  path: string,
+  query: URLSearchParams,
+  headers: Headers,
+  body: BodyInit | undefined,
+  init: RequestInit | undefined,
+): Promise<unknown> {
+  const search = query.toString();
+  const url = opts.baseUrl.replace(/\/$/, "") + path + (search ? "?" + search : "");
+  for (const [key, value] of Object.entries(opts.headers ?? {})) {
+    if (!headers.has(key)) {
+      headers.set(key, value);
+    }
+  }
+  const resp = await (opts.fetch ?? globalThis.fetch)(url, { ...init, method, headers, body });
+  const result = await readBody(resp);
+  if (!resp.ok) {
+    throw new ApiError(resp.status, result, resp);
+  }
+  return result;
+}
+
+`
+
+// TypeScript declarations of the named types, in the order they were met
+type tsGenerator struct {
+	decls map[string]string
+	order []string
+	names map[reflect.Type]string
+}
+
+func newTSGenerator() *tsGenerator {
+	return &tsGenerator{
+		decls: map[string]string{},
+		names: map[reflect.Type]string{},
+	}
+}
+
+// write the request interface, the error type and the function of the route
+func (g *tsGenerator) writeRoute(buf *bytes.Buffer, rt Route, name string) {
+	typeName := strings.ToUpper(name[:1]) + name[1:]
+	req := rt.req
+
+	// request interface, one property per section
+	var props []string
+	section := func(key string, params []reflectedKeyVal, field func(reflectedKeyVal) string) {
+		if len(params) == 0 {
+			return
+		}
+		optional := true
+		fields := make([]string, len(params))
+		for i, p := range params {
+			optional = optional && p.optional
+			fields[i] = tsProperty(p.alias, field(p), p.optional, p.description)
+		}
+		props = append(props, tsProperty(key, "{\n"+tsIndent(strings.Join(fields, "\n"))+"\n}", optional, ""))
+	}
+	param := func(p reflectedKeyVal) string {
+		return g.paramType(p)
+	}
+	section("path", req.pathParams, param)
+	section("query", req.queryParams, param)
+	section("headers", req.headers, param)
+	section("cookies", req.cookies, func(p reflectedKeyVal) string {
+		if p.typ == cookieType || p.typ == cookiePtrType {
+			return "string"
+		}
+		return g.paramType(p)
+	})
+	section("form", req.form, param)
+	section("multipart", req.multipart, func(p reflectedKeyVal) string {
+		switch p.typ {
+		case fileHeaderType:
+			return "Blob"
+		case fileHeadersType:
+			return "Blob[]"
+		}
+		return g.paramType(p)
+	})
+	switch {
+	case req.hasJSONBody():
+		props = append(props, tsProperty("body", g.typeOf(req.jsonBodyType), false, ""))
+	case req.hasBody():
+		props = append(props, tsProperty("body", g.typeOf(req.bodyType), false, ""))
+	}
+	hasReq := len(props) > 0
+	if hasReq {
+		fmt.Fprintf(buf, "export interface %sRequest {\n%s\n}\n\n", typeName, tsIndent(strings.Join(props, "\n")))
+	}
+
+	// error responses, discriminated by the status
+	errs := rt.errorResponses()
+	errTypes := make([]string, len(errs))
+	for i, e := range errs {
+		errTypes[i] = fmt.Sprintf("ApiError<%d, %s>", e.status, g.typeOf(e.typ))
+	}
+	fmt.Fprintf(buf, "export type %sError = %s;\n\n", typeName, strings.Join(errTypes, " | "))
+
+	// response
+	respType := "unknown"
+	status := rt.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	switch {
+	case bodylessStatus(status) || rt.respType == reflect.TypeOf(struct{}{}):
+		respType = "void"
+	case rt.respType.Kind() == reflect.String:
+		respType = "string"
+	case rt.respType.Implements(reflect.TypeOf((*Renderable)(nil)).Elem()):
+		// custom rendering, the body is unknown
+	default:
+		respType = g.typeOf(rt.respType)
+	}
+
+	// function
+	fmt.Fprintf(buf, "// %s %s\n", rt.Method, rt.Pattern)
+	fmt.Fprintf(buf, "// Throws %sError for the responses with a non-2xx status\n", typeName)
+	if hasReq {
+		fmt.Fprintf(buf, "export async function %s(opts: ClientOptions, req: %sRequest, init?: RequestInit): Promise<%s> {\n", name, typeName, respType)
+	} else {
+		fmt.Fprintf(buf, "export async function %s(opts: ClientOptions, init?: RequestInit): Promise<%s> {\n", name, respType)
+	}
+	fmt.Fprintf(buf, "  const path = %s;\n", tsPath(rt.Pattern))
+	buf.WriteString("  const query = new URLSearchParams();\n")
+	for _, p := range req.queryParams {
+		fmt.Fprintf(buf, "  appendParam((k, v) => query.append(k, v), %s, req.query%s);\n", tsString(p.alias), tsMember(p.alias, allOptional(req.queryParams)))
+	}
+	buf.WriteString("  const headers = new Headers(init?.headers);\n")
+	if respType != "void" && respType != "string" {
+		buf.WriteString("  headers.set(\"Accept\", \"application/json\");\n")
+	}
+	for _, p := range req.headers {
+		fmt.Fprintf(buf, "  appendParam((k, v) => headers.append(k, v), %s, req.headers%s);\n", tsString(http.CanonicalHeaderKey(p.alias)), tsMember(p.alias, allOptional(req.headers)))
+	}
+	if len(req.cookies) > 0 {
+		// browsers don't let fetch set the Cookie header, they send their own cookies
+		buf.WriteString("  const cookies: string[] = [];\n")
+		for _, p := range req.cookies {
+			fmt.Fprintf(buf, "  appendParam((k, v) => cookies.push(k + \"=\" + encodeURIComponent(v)), %s, req.cookies%s);\n", tsString(p.alias), tsMember(p.alias, allOptional(req.cookies)))
+		}
+		buf.WriteString("  if (cookies.length > 0) {\n    headers.set(\"Cookie\", cookies.join(\"; \"));\n  }\n")
+	}
+	body := "undefined"
+	switch {
+	case req.hasJSONBody() || req.hasBody():
+		buf.WriteString("  headers.set(\"Content-Type\", \"application/json\");\n")
+		body = "JSON.stringify(req.body)"
+	case req.hasForm():
+		buf.WriteString("  const form = new URLSearchParams();\n")
+		for _, p := range req.form {
+			fmt.Fprintf(buf, "  appendParam((k, v) => form.append(k, v), %s, req.form%s);\n", tsString(p.alias), tsMember(p.alias, allOptional(req.form)))
+		}
+		body = "form"
+	case req.hasMultipart():
+		// the boundary is set by fetch
+		buf.WriteString("  const form = new FormData();\n")
+		for _, p := range req.multipart {
+			if p.typ == fileHeaderType || p.typ == fileHeadersType {
+				fmt.Fprintf(buf, "  appendFile(form, %s, req.multipart%s);\n", tsString(p.alias), tsMember(p.alias, allOptional(req.multipart)))
+			} else {
+				fmt.Fprintf(buf, "  appendParam((k, v) => form.append(k, v), %s, req.multipart%s);\n", tsString(p.alias), tsMember(p.alias, allOptional(req.multipart)))
+			}
+		}
+		body = "form"
+	}
+	fmt.Fprintf(buf, "  return (await send(opts, %s, path, query, headers, %s, init)) as %s;\n}\n\n", tsString(rt.Method), body, respType)
+}
+
+// the type of the param, string enums become unions of their values
+func (g *tsGenerator) paramType(p reflectedKeyVal) string {
+	if p.typ == durationType || p.typ == reflect.SliceOf(durationType) {
+		// parsed by time.ParseDuration, e.g. "1m30s"
+		return strings.Replace(g.typeOf(p.typ), "number", "string", 1)
+	}
+	return tsEnum(g.typeOf(p.typ), p.typ, p.constraints)
+}
+
+// TypeScript type of the Go type as encoded by encoding/json
+func (g *tsGenerator) typeOf(t reflect.Type) string {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	typ := g.nonNullType(t)
+	if nullable {
+		return typ + " | null"
+	}
+	return typ
+}
+
+func (g *tsGenerator) nonNullType(t reflect.Type) string {
+	// well known types
+	switch t {
+	case problemType:
+		return g.registerProblem()
+	case reflect.TypeOf(uuid.UUID{}), reflect.TypeOf(time.Time{}):
+		return "string"
+	case reflect.TypeOf(time.Duration(0)):
+		return "number"
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return "string"
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		// custom json, the shape is unknown
+		return "unknown"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // base64
+		}
+		elem := g.typeOf(t.Elem())
+		if strings.ContainsAny(elem, " |") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + g.typeOf(t.Elem()) + ">"
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structType(t)
+		}
+		return g.register(t)
+	default:
+		// interfaces, funcs etc. can be anything
+		return "unknown"
+	}
+}
+
+// declare the named struct as an interface
+func (g *tsGenerator) register(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	base := tsIdentifier(t.Name())
+	name := base
+	// avoid collisions between types with the same name from different packages
+	for i := 2; g.isDeclared(name); i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.names[t] = name
+	g.order = append(g.order, name)
+	g.decls[name] = "" // placeholder for recursive types
+	g.decls[name] = fmt.Sprintf("export interface %s %s\n", name, g.structType(t))
+	return name
+}
+
+// declare the problem document, its extensions are free-form
+func (g *tsGenerator) registerProblem() string {
+	if name, ok := g.names[problemType]; ok {
+		return name
+	}
+	name := "Problem"
+	for i := 2; g.isDeclared(name); i++ {
+		name = "Problem" + strconv.Itoa(i)
+	}
+	members := strings.TrimSuffix(g.structType(reflect.TypeOf(problemMembers{})), "\n}")
+	g.names[problemType] = name
+	g.order = append(g.order, name)
+	g.decls[name] = fmt.Sprintf("export interface %s %s\n  [extension: string]: unknown;\n}\n", name, members)
+	return name
+}
+
+func (g *tsGenerator) isDeclared(name string) bool {
+	_, ok := g.decls[name]
+	return ok || tsReserved[name]
+}
+
+// names of the runtime helpers and the globals they use
+var tsReserved = map[string]bool{
+	"ClientOptions": true, "ApiError": true, "Param": true,
+	"Response": true, "Headers": true, "RequestInit": true, "BodyInit": true,
+	"Blob": true, "FormData": true, "URLSearchParams": true, "Error": true,
+}
+
+// object type of the struct, respecting the json tags
+func (g *tsGenerator) structType(t reflect.Type) string {
+	var props []string
+	g.collectFields(t, &props)
+	if len(props) == 0 {
+		return "{}"
+	}
+	return "{\n" + tsIndent(strings.Join(props, "\n")) + "\n}"
+}
+
+func (g *tsGenerator) collectFields(t reflect.Type, props *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// embedded structs without a name are flattened like encoding/json does
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.collectFields(ft, props)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		optional := field.Type.Kind() == reflect.Ptr
+		asString := false
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty", "omitzero":
+				optional = true
+			case "string":
+				asString = true
+			}
+		}
+
+		typ := g.typeOf(field.Type)
+		if asString {
+			typ = "string"
+		}
+		var description string
+		if tag := field.Tag.Get(_EZAPI_TAG_NAME); tag != "" {
+			// the tag errors are reported by ReflectReq
+			constraints, desc, _ := parseConstraints(tag)
+			typ = tsEnum(typ, field.Type, constraints)
+			description = desc
+		}
+		*props = append(*props, tsProperty(name, typ, optional, description))
+	}
+}
+
+// union of the enum values for the string fields
+func tsEnum(typ string, t reflect.Type, c *fieldConstraints) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if c == nil || len(c.enum) == 0 || t.Kind() != reflect.String || typ != "string" {
+		return typ
+	}
+	values := make([]string, len(c.enum))
+	for i, e := range c.enum {
+		values[i] = tsString(e)
+	}
+	return strings.Join(values, " | ")
+}
+
+// property of the object type with its description
+func tsProperty(name, typ string, optional bool, description string) string {
+	var prop strings.Builder
+	if description != "" {
+		prop.WriteString("/** " + strings.ReplaceAll(description, "*/", "*\\/") + " */\n")
+	}
+	prop.WriteString(tsKey(name))
+	if optional {
+		prop.WriteString("?")
+	}
+	prop.WriteString(": " + typ + ";")
+	return prop.String()
+}
+
+var tsIdentifierRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// the property name, quoted if it is not an identifier
+func tsKey(name string) string {
+	if tsIdentifierRe.MatchString(name) {
+		return name
+	}
+	return tsString(name)
+}
+
+// property access of the request object, e.g. .name, ?.name, ["X-Id"]
+// or ?.["X-Id"] with the optional chaining
+func tsMember(name string, optional bool) string {
+	switch {
+	case tsIdentifierRe.MatchString(name) && optional:
+		return "?." + name
+	case tsIdentifierRe.MatchString(name):
+		return "." + name
+	case optional:
+		return "?.[" + tsString(name) + "]"
+	}
+	return "[" + tsString(name) + "]"
+}
+
+// the section is optional if all its params are
+func allOptional(params []reflectedKeyVal) bool {
+	for _, p := range params {
+		if !p.optional {
+			return false
+		}
+	}
+	return true
+}
+
+// quoted string literal
+func tsString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// identifier from the Go type name, generic arguments are dropped
+func tsIdentifier(name string) string {
+	name, _, _ = strings.Cut(name, "[")
+	var id strings.Builder
+	for _, r := range name {
+		if r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			id.WriteRune(r)
+		}
+	}
+	if id.Len() == 0 {
+		return "Anonymous"
+	}
+	return id.String()
+}
+
+// indent the lines by two spaces
+func tsIndent(s string) string {
+	return "  " + strings.ReplaceAll(s, "\n", "\n  ")
+}
+
+// name of the route function, from the request struct or the method and the path
+func tsFuncName(rt Route) string {
+	name := ""
+	if rt.req.typ != nil {
+		name = tsIdentifier(rt.req.typ.Name())
+		for _, suffix := range []string{"Request", "Req"} {
+			if trimmed, ok := strings.CutSuffix(name, suffix); ok && trimmed != "" {
+				name = trimmed
+				break
+			}
+		}
+	}
+	if name == "" || name == "Anonymous" {
+		name = strings.ToLower(rt.Method)
+		for _, segment := range strings.Split(openAPIPath(rt.Pattern), "/") {
+			segment = strings.Trim(segment, "{}")
+			if segment = tsIdentifier(segment); segment != "Anonymous" {
+				name += strings.ToUpper(segment[:1]) + segment[1:]
+			}
+		}
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// expression building the path from the pattern and the path params
+func tsPath(pattern string) string {
+	path := openAPIPath(pattern)
+	var parts []string
+	for {
+		start := strings.IndexByte(path, '{')
+		end := strings.IndexByte(path, '}')
+		if start < 0 || end < start {
+			break
+		}
+		if start > 0 {
+			parts = append(parts, tsString(path[:start]))
+		}
+		name := path[start+1 : end]
+		rest := strings.Contains(pattern, "{"+name+"...}")
+		parts = append(parts, fmt.Sprintf("pathSegment(req.path%s, %t)", tsMember(name, false), rest))
+		path = path[end+1:]
+	}
+	if path != "" || len(parts) == 0 {
+		parts = append(parts, tsString(path))
+	}
+	return strings.Join(parts, " + ")
+}
@@ -0,0 +1,83 @@
+package ezapi
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type tsHeaderReq struct {
+	Headers struct {
+		RequestID string `ezapi:"X-Request-Id"`
+	} `ezapi:"header"`
+}
+
+type tsOptionalReq struct {
+	Query struct {
+		PageSize int `ezapi:"page-size,optional"`
+	} `ezapi:"query"`
+	Cookies struct {
+		Session string `ezapi:"session-id,optional"`
+	} `ezapi:"cookie"`
+}
+
+type tsRestReq struct {
+	Path struct {
+		File string `ezapi:"file"`
+	} `ezapi:"path"`
+}
+
+// the client of the routes with non-identifier param names
+func tsMemberClient() string {
+	r := NewRouter()
+	GET(r, "/trace", func(Context[tsHeaderReq]) (string, RespError) { return "", nil })
+	GET(r, "/page", func(Context[tsOptionalReq]) (string, RespError) { return "", nil })
+	GET(r, "/files/{file...}", func(Context[tsRestReq]) (string, RespError) { return "", nil })
+	return string(r.TypeScript())
+}
+
+func TestTypeScriptMemberAccess(t *testing.T) {
+	ts := tsMemberClient()
+
+	// the whole declarations and statements, the names are quoted in both
+	for _, want := range []string{
+		`"X-Request-Id": string;`,
+		`appendParam((k, v) => headers.append(k, v), "X-Request-Id", req.headers["X-Request-Id"]);`,
+		`"page-size"?: number;`,
+		`appendParam((k, v) => query.append(k, v), "page-size", req.query?.["page-size"]);`,
+		`"session-id"?: string;`,
+		`appendParam((k, v) => cookies.push(k + "=" + encodeURIComponent(v)), "session-id", req.cookies?.["session-id"]);`,
+		`pathSegment(req.path.file, true)`,
+	} {
+		if !strings.Contains(ts, want) {
+			t.Errorf("expected %s in the client", want)
+		}
+	}
+	// every member of the request is an identifier after a dot or a
+	// quoted string in brackets, a dot before the brackets is a syntax
+	// error unless it is optional chaining
+	member := regexp.MustCompile(`req(\??\.[A-Za-z_$][\w$]*|(\?\.)?\["[^"]*"\])+`)
+	for _, access := range regexp.MustCompile(`req[.?\[][^,;)\s]*`).FindAllString(ts, -1) {
+		if !member.MatchString(access) || member.FindString(access) != access {
+			t.Errorf("invalid member access %q in the client", access)
+		}
+	}
+}
+
+func TestTypeScriptCompiles(t *testing.T) {
+	tsc, err := exec.LookPath("tsc")
+	if err != nil {
+		t.Skip("tsc is not installed, TestTypeScriptMemberAccess checks the output only")
+	}
+	file := filepath.Join(t.TempDir(), "client.ts")
+	if err := os.WriteFile(file, []byte(tsMemberClient()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(tsc, "--noEmit", "--strict", "--target", "es2020", "--lib", "es2020,dom", file).CombinedOutput()
+	if err != nil {
+		t.Errorf("tsc: %v\n%s", err, out)
+	}
+}
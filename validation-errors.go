@@ -0,0 +1,103 @@
+package ezapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// FieldError is a binding or validation failure of a single request field
+type FieldError struct {
+	// Where the field is, e.g. "path.id", "query.limit" or "body"
+	Location string `json:"location"`
+	Reason   string `json:"reason"`
+	// The offending value, if any
+	Value any `json:"value,omitempty"`
+
+	Err error `json:"-"`
+}
+
+func (e FieldError) Error() string {
+	if e.Location == "" {
+		return e.Reason
+	}
+	return e.Location + ": " + e.Reason
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// helper function to create the field error of the section param
+func newFieldError(section, alias string, value any, reason error, debugErr error) FieldError {
+	location := section
+	if alias != "" {
+		location += "." + alias
+	}
+	return FieldError{
+		Location: location,
+		Reason:   reason.Error(),
+		Value:    value,
+		Err:      errors.Join(reason, debugErr),
+	}
+}
+
+// ValidationErrors carries every binding or validation failure of the request
+type ValidationErrors struct {
+	// Status code of the response, 400 if not set
+	Status int
+	Errors []FieldError
+}
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+func (e ValidationErrors) Render(ctx BaseContext) error {
+	status := e.Status
+	if status == 0 {
+		status = http.StatusBadRequest
+	}
+	errorBody := EzAPIError{Message: "Invalid request", Errors: e.Errors}
+	return writeJSON(ctx.GetW(), status, "application/json", errorBody)
+}
+
+// reports whether the error is a field failure that can be combined
+// with the others into a single validation response
+func isValidationError(err error) bool {
+	var verrs ValidationErrors
+	var ferr FieldError
+	return errors.As(err, &verrs) || errors.As(err, &ferr)
+}
+
+// combine the failed field validations into a single 422 response
+func combineValidationErrors(locations []string, errs []RespError) ValidationErrors {
+	combined := ValidationErrors{Status: http.StatusUnprocessableEntity}
+	for i, err := range errs {
+		var verrs ValidationErrors
+		if errors.As(err, &verrs) {
+			combined.Errors = append(combined.Errors, verrs.Errors...)
+			continue
+		}
+		var ferr FieldError
+		if errors.As(err, &ferr) {
+			if ferr.Location == "" {
+				ferr.Location = locations[i]
+			}
+			combined.Errors = append(combined.Errors, ferr)
+		}
+	}
+	return combined
+}